@@ -0,0 +1,238 @@
+// Package api serves the language museums dataset as a read-only JSON REST
+// API beside the MCP endpoint in HTTP/HTTPS mode. The endpoints are
+// described in museums-openapi.yml at the root of the repository.
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+const (
+	// DefaultLimit is the page size of /api/museums when limit is unset.
+	DefaultLimit = 20
+	// MaxLimit bounds the page size of /api/museums.
+	MaxLimit = 100
+)
+
+// allowedMethods are the methods of every endpoint.
+const allowedMethods = "GET, HEAD, OPTIONS"
+
+// Page is one page of /api/museums.
+type Page struct {
+	Total   int      `json:"total"`
+	Offset  int      `json:"offset"`
+	Limit   int      `json:"limit"`
+	Results []Result `json:"results"`
+}
+
+// Result is a museum in a list, with its search score when the list was
+// searched by text.
+type Result struct {
+	museums.Detail
+	Score *float64 `json:"score,omitempty"`
+}
+
+// Country is one entry of /api/countries.
+type Country struct {
+	Country string `json:"country"`
+	Code    string `json:"code,omitempty"`
+	Museums int    `json:"museums"`
+}
+
+// Error is the body of every failed request. Codes are those of the MCP
+// tools' error results.
+type Error struct {
+	Code        client.ErrorCode `json:"code"`
+	Message     string           `json:"message"`
+	Suggestions []string         `json:"suggestions,omitempty"`
+}
+
+// Handler serves the API. Responses are computed from the store's current
+// index, so a reload is served as soon as it is swapped in.
+type Handler struct {
+	store *museums.Store
+	cors  CORS
+	mux   *http.ServeMux
+}
+
+// NewHandler returns the API handler for store with the given CORS policy.
+// Mount it at "/api/".
+func NewHandler(store *museums.Store, cors CORS) *Handler {
+	h := &Handler{store: store, cors: cors, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /api/museums", h.listMuseums)
+	h.mux.HandleFunc("GET /api/museums/{slug}", h.getMuseum)
+	h.mux.HandleFunc("GET /api/countries", h.listCountries)
+	for _, pattern := range []string{"/api/museums", "/api/museums/{slug}", "/api/countries"} {
+		h.mux.HandleFunc("OPTIONS "+pattern, options)
+	}
+	h.mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, Error{Code: client.CodeNotFound, Message: "no such endpoint: " + r.URL.Path})
+	})
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.cors.handle(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+		w.Header().Set("Allow", allowedMethods)
+		writeError(w, http.StatusMethodNotAllowed, Error{Code: client.CodeBadInput, Message: "method not allowed: " + r.Method})
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// options answers an OPTIONS request that is not a CORS preflight with
+// the methods the endpoint allows.
+func options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Allow", allowedMethods)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listMuseums(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := museums.Query{
+		Country:  params.Get("country"),
+		City:     params.Get("city"),
+		Category: params.Get("category"),
+		Language: params.Get("language"),
+	}
+	var err error
+	if q.FoundedFrom, err = intParam(params.Get("foundedFrom"), 0); err != nil {
+		writeError(w, http.StatusBadRequest, Error{Code: client.CodeBadInput, Message: "foundedFrom: " + err.Error()})
+		return
+	}
+	if q.FoundedTo, err = intParam(params.Get("foundedTo"), 0); err != nil {
+		writeError(w, http.StatusBadRequest, Error{Code: client.CodeBadInput, Message: "foundedTo: " + err.Error()})
+		return
+	}
+	limit, err := intParam(params.Get("limit"), DefaultLimit)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		writeError(w, http.StatusBadRequest, Error{Code: client.CodeBadInput, Message: fmt.Sprintf("limit must be a whole number from 1 to %d", MaxLimit)})
+		return
+	}
+	offset, err := intParam(params.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, Error{Code: client.CodeBadInput, Message: "offset must be a whole number of at least 0"})
+		return
+	}
+
+	// Without text every museum scores the same and the order is by slug.
+	text := strings.TrimSpace(params.Get("q"))
+	matches := h.store.Index().Search(text, q)
+	page := Page{Total: len(matches), Offset: offset, Limit: limit, Results: []Result{}}
+	for i := offset; i < len(matches) && i < offset+limit; i++ {
+		res := Result{Detail: matches[i].Museum.Detail()}
+		if text != "" {
+			score := matches[i].Score
+			res.Score = &score
+		}
+		page.Results = append(page.Results, res)
+	}
+	writeJSON(w, r, page)
+}
+
+func (h *Handler) getMuseum(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	ix := h.store.Index()
+	m, ok := ix.Get(slug)
+	if !ok {
+		writeError(w, http.StatusNotFound, Error{
+			Code:        client.CodeNotFound,
+			Message:     fmt.Sprintf("no museum with slug %q", slug),
+			Suggestions: ix.Suggest(slug, 3),
+		})
+		return
+	}
+	writeJSON(w, r, m.Detail())
+}
+
+func (h *Handler) listCountries(w http.ResponseWriter, r *http.Request) {
+	ix := h.store.Index()
+	result := []Country{}
+	for _, c := range ix.Countries() {
+		list := ix.ByCountry(c)
+		entry := Country{Country: c, Museums: len(list)}
+		for _, m := range list {
+			if m.CountryCode != "" {
+				entry.Code = m.CountryCode
+				break
+			}
+		}
+		result = append(result, entry)
+	}
+	writeJSON(w, r, result)
+}
+
+func intParam(s string, fallback int) (int, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", s)
+	}
+	return n, nil
+}
+
+// writeJSON writes v with a strong ETag derived from the body, answering a
+// matching If-None-Match with 304 Not Modified and no body.
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, Error{Code: client.CodeDecodeError, Message: err.Error()})
+		return
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	// Clients may reuse a response only after revalidating it.
+	header.Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for
+// (RFC 9110, section 13.1.2).
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, e Error) {
+	var body bytes.Buffer
+	json.NewEncoder(&body).Encode(e)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body.Bytes())
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+// testHandler serves 25 numbered museums in Paris besides the Gutenberg
+// Museum and the Grimmwelt.
+func testHandler(cors CORS) *Handler {
+	list := []museums.Museum{
+		{Slug: "gutenberg-museum", Name: "Gutenberg Museum", LastUpdated: "1900", Category: "writing", Location: "Mainz, Germany", CountryCode: "DE"},
+		{Slug: "grimmwelt", Name: "Grimmwelt", LastUpdated: "2015", Category: "persons", Location: "Kassel, Germany"},
+	}
+	for i := range 25 {
+		list = append(list, museums.Museum{
+			Slug:        fmt.Sprintf("museum-%02d", i),
+			Name:        fmt.Sprintf("Museum %02d", i),
+			LastUpdated: "2000",
+			Category:    "one-language",
+			Location:    "Paris, France",
+		})
+	}
+	return NewHandler(museums.NewStore(museums.NewIndex(list)), cors)
+}
+
+func serve(h http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func decodeBody[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("%s: %v", w.Body, err)
+	}
+	return v
+}
+
+func slugs(page Page) []string {
+	var out []string
+	for _, r := range page.Results {
+		out = append(out, r.Slug)
+	}
+	return out
+}
+
+func TestListMuseums(t *testing.T) {
+	h := testHandler(CORS{})
+	tests := []struct {
+		query        string
+		total, count int
+		first        string
+	}{
+		{"", 27, DefaultLimit, "grimmwelt"},
+		{"?limit=100", 27, 27, "grimmwelt"},
+		{"?limit=5&offset=5", 27, 5, "museum-03"},
+		{"?offset=26", 27, 1, "museum-24"},
+		{"?offset=27", 27, 0, ""},
+		{"?offset=1000", 27, 0, ""},
+		{"?country=germany", 2, 2, "grimmwelt"},
+		{"?country=de", 1, 1, "gutenberg-museum"},
+		{"?foundedFrom=1800&foundedTo=1950", 1, 1, "gutenberg-museum"},
+	}
+	for _, tt := range tests {
+		w := serve(h, "GET", "/api/museums"+tt.query, nil)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tt.query, w.Code, w.Body)
+			continue
+		}
+		page := decodeBody[Page](t, w)
+		if page.Total != tt.total || len(page.Results) != tt.count || (tt.count > 0 && page.Results[0].Slug != tt.first) {
+			t.Errorf("%s: total %d, results %q; want total %d, %d results from %s", tt.query, page.Total, slugs(page), tt.total, tt.count, tt.first)
+		}
+		if page.Results == nil {
+			t.Errorf("%s: results is null", tt.query)
+		}
+	}
+
+	for _, query := range []string{"limit=0", "limit=101", "limit=x", "offset=-1", "offset=x", "foundedFrom=x", "foundedTo=1.5"} {
+		w := serve(h, "GET", "/api/museums?"+query, nil)
+		if e := decodeBody[Error](t, w); w.Code != http.StatusBadRequest || e.Code != client.CodeBadInput {
+			t.Errorf("%s: status %d, code %s; want 400 %s", query, w.Code, e.Code, client.CodeBadInput)
+		}
+	}
+}
+
+func TestSearchScores(t *testing.T) {
+	w := serve(testHandler(CORS{}), "GET", "/api/museums?q=gutenburg", nil)
+	page := decodeBody[Page](t, w)
+	if len(page.Results) != 1 || page.Results[0].Slug != "gutenberg-museum" || page.Results[0].Score == nil {
+		t.Fatalf("search results %s", w.Body)
+	}
+	if score := *page.Results[0].Score; score <= 0 || score >= 1 {
+		t.Errorf("fuzzy match scored %v", score)
+	}
+	w = serve(testHandler(CORS{}), "GET", "/api/museums?limit=1", nil)
+	if page := decodeBody[Page](t, w); page.Results[0].Score != nil {
+		t.Errorf("unsearched list has scores: %s", w.Body)
+	}
+}
+
+func TestGetMuseum(t *testing.T) {
+	h := testHandler(CORS{})
+	w := serve(h, "GET", "/api/museums/gutenberg-museum", nil)
+	if d := decodeBody[museums.Detail](t, w); w.Code != http.StatusOK || d.Name != "Gutenberg Museum" || d.City != "Mainz" || d.FoundingYear != 1900 {
+		t.Errorf("status %d: %s", w.Code, w.Body)
+	}
+
+	w = serve(h, "GET", "/api/museums/gutenburg-museum", nil)
+	e := decodeBody[Error](t, w)
+	if w.Code != http.StatusNotFound || e.Code != client.CodeNotFound || !slices.Equal(e.Suggestions, []string{"gutenberg-museum"}) {
+		t.Errorf("status %d: %s", w.Code, w.Body)
+	}
+	w = serve(h, "GET", "/api/museums/zzzzzzzzzzzzzzzz", nil)
+	if e := decodeBody[Error](t, w); w.Code != http.StatusNotFound || e.Suggestions != nil {
+		t.Errorf("status %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestListCountries(t *testing.T) {
+	w := serve(testHandler(CORS{}), "GET", "/api/countries", nil)
+	want := []Country{{Country: "France", Museums: 25}, {Country: "Germany", Code: "DE", Museums: 2}}
+	if got := decodeBody[[]Country](t, w); !slices.Equal(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	w := serve(testHandler(CORS{}), "GET", "/api/nope", nil)
+	if e := decodeBody[Error](t, w); w.Code != http.StatusNotFound || e.Code != client.CodeNotFound {
+		t.Errorf("status %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestMethods(t *testing.T) {
+	h := testHandler(CORS{})
+	get := serve(h, "GET", "/api/museums/grimmwelt", nil)
+
+	w := serve(h, "HEAD", "/api/museums/grimmwelt", nil)
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("HEAD: status %d, body %q", w.Code, w.Body)
+	}
+	for _, key := range []string{"ETag", "Content-Length", "Content-Type"} {
+		if w.Header().Get(key) != get.Header().Get(key) {
+			t.Errorf("HEAD: %s %q, GET sent %q", key, w.Header().Get(key), get.Header().Get(key))
+		}
+	}
+
+	w = serve(h, "POST", "/api/museums", nil)
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != allowedMethods {
+		t.Errorf("POST: status %d, Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	for _, target := range []string{"/api/museums", "/api/museums/grimmwelt", "/api/countries"} {
+		w := serve(h, "OPTIONS", target, nil)
+		if w.Code != http.StatusNoContent || w.Header().Get("Allow") != allowedMethods || w.Body.Len() != 0 {
+			t.Errorf("OPTIONS %s: status %d, Allow %q, body %q", target, w.Code, w.Header().Get("Allow"), w.Body)
+		}
+	}
+	if w := serve(h, "OPTIONS", "/api/nope", nil); w.Code != http.StatusNotFound {
+		t.Errorf("OPTIONS /api/nope: status %d", w.Code)
+	}
+}
+
+func TestETag(t *testing.T) {
+	h := testHandler(CORS{})
+	w := serve(h, "GET", "/api/museums/grimmwelt", nil)
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("headers %v", w.Header())
+	}
+	if other := serve(h, "GET", "/api/museums/gutenberg-museum", nil).Header().Get("ETag"); other == etag {
+		t.Errorf("different bodies share ETag %s", etag)
+	}
+
+	tests := []struct {
+		ifNoneMatch string
+		want        int
+	}{
+		{etag, http.StatusNotModified},
+		{"W/" + etag, http.StatusNotModified},
+		{"*", http.StatusNotModified},
+		{`"other", ` + etag, http.StatusNotModified},
+		{`"other"`, http.StatusOK},
+		{etag[1 : len(etag)-1], http.StatusOK},
+	}
+	for _, tt := range tests {
+		for _, method := range []string{"GET", "HEAD"} {
+			w := serve(h, method, "/api/museums/grimmwelt", map[string]string{"If-None-Match": tt.ifNoneMatch})
+			if w.Code != tt.want {
+				t.Errorf("%s If-None-Match %s: status %d, want %d", method, tt.ifNoneMatch, w.Code, tt.want)
+			}
+			if w.Code == http.StatusNotModified && (w.Body.Len() != 0 || w.Header().Get("ETag") != etag) {
+				t.Errorf("%s If-None-Match %s: 304 with body %q, ETag %q", method, tt.ifNoneMatch, w.Body, w.Header().Get("ETag"))
+			}
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	h := testHandler(ParseCORS("https://example.org/, https://app.example.org"))
+	preflight := map[string]string{"Access-Control-Request-Method": "GET"}
+
+	tests := []struct {
+		origin, allow string
+	}{
+		{"https://example.org", "https://example.org"},
+		{"https://app.example.org", "https://app.example.org"},
+		{"https://evil.example.com", ""},
+	}
+	for _, tt := range tests {
+		preflight["Origin"] = tt.origin
+		w := serve(h, "OPTIONS", "/api/museums", preflight)
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != tt.allow {
+			t.Errorf("preflight from %s: status %d, allowed %q; want 204, %q", tt.origin, w.Code, w.Header().Get("Access-Control-Allow-Origin"), tt.allow)
+		}
+		if methods := w.Header().Get("Access-Control-Allow-Methods"); (methods != "") != (tt.allow != "") {
+			t.Errorf("preflight from %s: Access-Control-Allow-Methods %q", tt.origin, methods)
+		}
+		if w.Header().Get("Allow") != "" {
+			t.Errorf("preflight from %s answered as a plain OPTIONS request", tt.origin)
+		}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Errorf("preflight from %s: Vary %q", tt.origin, w.Header().Get("Vary"))
+		}
+
+		w = serve(h, "GET", "/api/countries", map[string]string{"Origin": tt.origin})
+		if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != tt.allow {
+			t.Errorf("GET from %s: status %d, allowed %q", tt.origin, w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+		}
+		if exposed := w.Header().Get("Access-Control-Expose-Headers"); (exposed == "ETag") != (tt.allow != "") {
+			t.Errorf("GET from %s: Access-Control-Expose-Headers %q", tt.origin, exposed)
+		}
+	}
+
+	any := testHandler(ParseCORS("*"))
+	w := serve(any, "GET", "/api/countries", map[string]string{"Origin": "https://evil.example.com"})
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Vary") != "" {
+		t.Errorf("any origin: headers %v", w.Header())
+	}
+	w = serve(testHandler(CORS{}), "GET", "/api/countries", map[string]string{"Origin": "https://example.org"})
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("CORS disabled: headers %v", w.Header())
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORS is the cross-origin policy of the API. The dataset is public and
+// the API takes no credentials, so allowing every origin is safe; a list
+// restricts browsers to known web apps.
+type CORS struct {
+	// AllowedOrigins lists the origins, such as "https://example.org",
+	// that browsers may call the API from. "*" allows any origin and an
+	// empty list disables CORS.
+	AllowedOrigins []string
+}
+
+// ParseCORS reads a comma-separated origin list such as the value of
+// CORS_ALLOWED_ORIGINS.
+func ParseCORS(origins string) CORS {
+	var c CORS
+	for _, o := range strings.Split(origins, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			c.AllowedOrigins = append(c.AllowedOrigins, strings.TrimSuffix(o, "/"))
+		}
+	}
+	return c
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or
+// "" if it is not allowed.
+func (c CORS) allowOrigin(origin string) string {
+	if slices.Contains(c.AllowedOrigins, "*") {
+		return "*"
+	}
+	if slices.Contains(c.AllowedOrigins, origin) {
+		return origin
+	}
+	return ""
+}
+
+// handle sets the CORS headers of a response and answers preflight
+// requests, reporting whether the request has been answered.
+func (c CORS) handle(w http.ResponseWriter, r *http.Request) bool {
+	header := w.Header()
+	if !slices.Contains(c.AllowedOrigins, "*") {
+		header.Add("Vary", "Origin")
+	}
+	origin := r.Header.Get("Origin")
+	allowed := ""
+	if origin != "" {
+		allowed = c.allowOrigin(origin)
+	}
+	if allowed != "" {
+		header.Set("Access-Control-Allow-Origin", allowed)
+		header.Set("Access-Control-Expose-Headers", "ETag")
+	}
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+	if allowed != "" {
+		header.Set("Access-Control-Allow-Methods", allowedMethods)
+		header.Set("Access-Control-Allow-Headers", "If-None-Match")
+		header.Set("Access-Control-Max-Age", "86400")
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
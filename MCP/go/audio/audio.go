@@ -0,0 +1,304 @@
+// Package audio fetches pronunciation files. The file URLs the API returns
+// expire quickly, so the word_audio tool can return the files themselves
+// as MCP audio content or, in HTTP mode, link them through a short-lived
+// /audio/{id} endpoint that serves them from memory.
+package audio
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+)
+
+const (
+	// cacheBytes bounds the memory held by cached files, linked or not.
+	cacheBytes = 64 << 20
+	// maxLinks bounds the number of live links.
+	maxLinks = 4096
+)
+
+// A File is a fetched pronunciation file.
+type File struct {
+	Data     []byte
+	MIMEType string
+}
+
+// store holds the fetched files by URL and the links by ID, each until it
+// expires. Files are evicted least recently used first once they exceed
+// cacheBytes. Links name cached files, so that eviction bounds their memory
+// too; a link whose file was evicted is gone. Files are only fetched from
+// the hosts allowed.
+type store struct {
+	client *http.Client
+
+	mu       sync.Mutex
+	hosts    []string
+	maxBytes int64
+	ttl      time.Duration
+	files    map[string]*list.Element
+	order    *list.List // of *cached, front is most recently used
+	size     int64
+	links    map[string]link
+	// linkOrder holds the link IDs oldest first. Links share one TTL, so
+	// that is also the order they expire in.
+	linkOrder []string
+}
+
+type cached struct {
+	url     string
+	file    File
+	expires time.Time
+}
+
+type link struct {
+	url     string
+	expires time.Time
+}
+
+var defaultStore = newStore(config.DefaultAudioMaxBytes, config.DefaultAudioTTL, config.DefaultAudioHosts...)
+
+func newStore(maxBytes int64, ttl time.Duration, hosts ...string) *store {
+	s := &store{hosts: hosts}
+	s.client = &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !s.allowed(req.URL) {
+				return fmt.Errorf("redirect to %s is not an allowed audio host", req.URL.Host)
+			}
+			return nil
+		},
+	}
+	s.reset(maxBytes, ttl)
+	return s
+}
+
+// SetHosts sets the hosts audio files may be fetched from. A host also
+// allows its subdomains, so "wordnik.com" allows "api.wordnik.com".
+// File URLs come from the API, whose base URL callers choose in HTTP mode,
+// so other hosts, such as internal addresses, are refused.
+func SetHosts(hosts ...string) {
+	defaultStore.mu.Lock()
+	defer defaultStore.mu.Unlock()
+	defaultStore.hosts = hosts
+}
+
+// allowed reports whether u is on one of the hosts allowed.
+func (s *store) allowed(u *url.URL) bool {
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range s.hosts {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLimits sets the size limit of one file and how long files are cached
+// and links served. It forgets the files and links so far.
+func SetLimits(maxBytes int64, ttl time.Duration) {
+	defaultStore.reset(maxBytes, ttl)
+}
+
+func (s *store) reset(maxBytes int64, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxBytes, s.ttl = maxBytes, ttl
+	s.files, s.order, s.size = map[string]*list.Element{}, list.New(), 0
+	s.links, s.linkOrder = map[string]link{}, nil
+}
+
+// Fetch returns the file at fileURL, from the cache if it was fetched
+// within the TTL. Failures are *client.Error values: a file larger than the
+// size limit is bad_input, and an expired or missing one not_found.
+func Fetch(ctx context.Context, fileURL string) (File, error) {
+	return defaultStore.fetch(ctx, fileURL)
+}
+
+// Link fetches the file at fileURL and returns the ID that Handler serves
+// it under until the returned expiry.
+func Link(ctx context.Context, fileURL string) (string, time.Time, error) {
+	return defaultStore.link(ctx, fileURL)
+}
+
+// Handler serves the linked files at /audio/{id}.
+func Handler() http.Handler {
+	return defaultStore
+}
+
+func (s *store) fetch(ctx context.Context, fileURL string) (File, error) {
+	if f, ok := s.cachedFile(fileURL); ok {
+		return f, nil
+	}
+	s.mu.Lock()
+	maxBytes := s.maxBytes
+	s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil || (req.URL.Scheme != "http" && req.URL.Scheme != "https") {
+		return File{}, client.BadInput("invalid audio file URL %q", fileURL)
+	}
+	if !s.allowed(req.URL) {
+		return File{}, client.BadInput("audio file host %q is not allowed", req.URL.Host)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return File{}, &client.Error{Code: client.CodeUpstreamUnavailable, Message: "audio request failed", Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return File{}, client.Classify(resp.StatusCode, body)
+	}
+	mimeType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mimeType, "audio/") {
+		return File{}, &client.Error{Code: client.CodeDecodeError, Status: resp.StatusCode, Message: fmt.Sprintf("audio file has Content-Type %q, not audio", resp.Header.Get("Content-Type"))}
+	}
+	if resp.ContentLength > maxBytes {
+		return File{}, tooLarge(resp.ContentLength, maxBytes)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return File{}, &client.Error{Code: client.CodeUpstreamUnavailable, Status: resp.StatusCode, Message: "failed to read audio file", Err: err}
+	}
+	if int64(len(data)) > maxBytes {
+		return File{}, tooLarge(-1, maxBytes)
+	}
+	f := File{Data: data, MIMEType: mimeType}
+	s.put(fileURL, f)
+	return f, nil
+}
+
+func tooLarge(size, maxBytes int64) *client.Error {
+	if size < 0 {
+		return client.BadInput("audio file exceeds the limit of %d bytes", maxBytes)
+	}
+	return client.BadInput("audio file of %d bytes exceeds the limit of %d bytes", size, maxBytes)
+}
+
+func (s *store) cachedFile(fileURL string) (File, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.files[fileURL]
+	if !ok {
+		return File{}, false
+	}
+	c := el.Value.(*cached)
+	if time.Now().After(c.expires) {
+		s.remove(el)
+		return File{}, false
+	}
+	s.order.MoveToFront(el)
+	return c.file, true
+}
+
+func (s *store) put(fileURL string, f File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.files[fileURL]; ok {
+		s.remove(el)
+	}
+	s.files[fileURL] = s.order.PushFront(&cached{url: fileURL, file: f, expires: time.Now().Add(s.ttl)})
+	s.size += int64(len(f.Data))
+	for s.size > cacheBytes && s.order.Len() > 1 {
+		s.remove(s.order.Back())
+	}
+}
+
+// remove drops a cached file. s.mu must be held.
+func (s *store) remove(el *list.Element) {
+	c := s.order.Remove(el).(*cached)
+	delete(s.files, c.url)
+	s.size -= int64(len(c.file.Data))
+}
+
+func (s *store) link(ctx context.Context, fileURL string) (string, time.Time, error) {
+	if _, err := s.fetch(ctx, fileURL); err != nil {
+		return "", time.Time{}, err
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", time.Time{}, err
+	}
+	id := hex.EncodeToString(b[:])
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	expires := now.Add(s.ttl)
+	// The file must stay cached as long as the link lasts.
+	if el, ok := s.files[fileURL]; ok {
+		el.Value.(*cached).expires = expires
+		s.order.MoveToFront(el)
+	}
+	for len(s.linkOrder) > 0 && (len(s.linkOrder) >= maxLinks || now.After(s.links[s.linkOrder[0]].expires)) {
+		delete(s.links, s.linkOrder[0])
+		s.linkOrder = s.linkOrder[1:]
+	}
+	s.links[id] = link{url: fileURL, expires: expires}
+	s.linkOrder = append(s.linkOrder, id)
+	return id, expires, nil
+}
+
+// ServeHTTP serves GET /audio/{id}. Unknown and expired IDs, and those
+// whose file was evicted, get 404.
+func (s *store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/audio/")
+	s.mu.Lock()
+	l, ok := s.links[id]
+	s.mu.Unlock()
+	if !ok || time.Now().After(l.expires) {
+		http.NotFound(w, r)
+		return
+	}
+	f, ok := s.cachedFile(l.url)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	maxAge := int(time.Until(l.expires).Seconds())
+	w.Header().Set("Content-Type", f.MIMEType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.Data)))
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+	if r.Method == http.MethodGet {
+		w.Write(f.Data)
+	}
+}
+
+type proxyKey struct{}
+
+// WithProxyURL returns a context in which links are served under base, the
+// public URL of the HTTP server, as in base+"/audio/"+id.
+func WithProxyURL(ctx context.Context, base string) context.Context {
+	return context.WithValue(ctx, proxyKey{}, strings.TrimSuffix(base, "/"))
+}
+
+// ProxyURL returns the public URL of the HTTP server that ctx carries, or
+// false outside HTTP mode.
+func ProxyURL(ctx context.Context) (string, bool) {
+	base, ok := ctx.Value(proxyKey{}).(string)
+	return base, ok && base != ""
+}
@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wordnik/mcp-server/client"
+)
+
+func fileServer(t *testing.T, hits *int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		switch r.URL.Path {
+		case "/cat.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write([]byte("ID3 cat"))
+		case "/page.mp3":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>"))
+		case "/elsewhere.mp3":
+			http.Redirect(w, r, "http://localhost"+strings.TrimPrefix(r.Host, "127.0.0.1")+"/cat.mp3", http.StatusFound)
+		case "/cat.ogg":
+			w.Header().Set("Content-Type", "audio/ogg; codecs=vorbis")
+			w.Write([]byte("OggS cat"))
+		case "/big.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write([]byte(strings.Repeat("x", 100)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetch(t *testing.T) {
+	var hits int
+	srv := fileServer(t, &hits)
+	s := newStore(64, time.Minute, "127.0.0.1")
+	ctx := context.Background()
+
+	for range 2 {
+		f, err := s.fetch(ctx, srv.URL+"/cat.mp3")
+		if err != nil || string(f.Data) != "ID3 cat" || f.MIMEType != "audio/mpeg" {
+			t.Fatalf("fetch = %q %q, %v", f.Data, f.MIMEType, err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("%d requests for a cached file, want 1", hits)
+	}
+	if f, err := s.fetch(ctx, srv.URL+"/cat.ogg"); err != nil || f.MIMEType != "audio/ogg" {
+		t.Errorf("ogg = %q, %v", f.MIMEType, err)
+	}
+
+	tests := []struct {
+		url  string
+		code client.ErrorCode
+	}{
+		{srv.URL + "/big.mp3", client.CodeBadInput},
+		{srv.URL + "/gone.mp3", client.CodeNotFound},
+		{srv.URL + "/page.mp3", client.CodeDecodeError},
+		{"file:///etc/passwd", client.CodeBadInput},
+		{"http://169.254.169.254/latest/meta-data/", client.CodeBadInput},
+		{strings.Replace(srv.URL, "127.0.0.1", "localhost", 1) + "/cat.mp3", client.CodeBadInput},
+		{srv.URL + "/elsewhere.mp3", client.CodeUpstreamUnavailable},
+		{"http://127.0.0.1:1/cat.mp3", client.CodeUpstreamUnavailable},
+	}
+	for _, tt := range tests {
+		if _, err := s.fetch(ctx, tt.url); client.CodeOf(err) != tt.code {
+			t.Errorf("fetch %s = %v, want %s", tt.url, err, tt.code)
+		}
+	}
+}
+
+func TestAllowedHosts(t *testing.T) {
+	s := newStore(64, time.Minute, "wordnik.com", "127.0.0.1")
+	tests := map[string]bool{
+		"https://api.wordnik.com/v4/audioFile.mp3/x": true,
+		"https://WORDNIK.com./x":                     true,
+		"http://127.0.0.1:8089/audio/cat.mp3":        true,
+		"https://notwordnik.com/x":                   false,
+		"https://wordnik.com.evil.example/x":         false,
+		"http://169.254.169.254/":                    false,
+		"http://[::1]/x":                             false,
+	}
+	for raw, want := range tests {
+		u, _ := url.Parse(raw)
+		if got := s.allowed(u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	var hits int
+	srv := fileServer(t, &hits)
+	s := newStore(64, time.Millisecond, "127.0.0.1")
+	s.fetch(context.Background(), srv.URL+"/cat.mp3")
+	time.Sleep(5 * time.Millisecond)
+	s.fetch(context.Background(), srv.URL+"/cat.mp3")
+	if hits != 2 {
+		t.Errorf("%d requests, want 2 after the cache expired", hits)
+	}
+}
+
+func TestLink(t *testing.T) {
+	var hits int
+	srv := fileServer(t, &hits)
+	s := newStore(64, 50*time.Millisecond, "127.0.0.1")
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	id, expires, err := s.link(context.Background(), srv.URL+"/cat.mp3")
+	if err != nil || len(id) != 32 || time.Until(expires) <= 0 {
+		t.Fatalf("link = %q %v, %v", id, expires, err)
+	}
+	srv.Close() // links are served from memory
+	resp, err := http.Get(proxy.URL + "/audio/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ID3 cat" || resp.Header.Get("Content-Type") != "audio/mpeg" {
+		t.Errorf("GET link = %d %q %q", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+
+	for _, target := range []string{"/audio/unknown", "/audio/" + id} {
+		if target == "/audio/"+id {
+			time.Sleep(time.Until(expires) + time.Millisecond)
+		}
+		resp, err := http.Get(proxy.URL + target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestLinksAreBounded(t *testing.T) {
+	var hits int
+	srv := fileServer(t, &hits)
+	s := newStore(64, time.Minute, "127.0.0.1")
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+	ctx := context.Background()
+
+	first, _, err := s.link(ctx, srv.URL+"/cat.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range maxLinks {
+		if _, _, err := s.link(ctx, srv.URL+"/cat.mp3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.links) != maxLinks || len(s.linkOrder) != maxLinks {
+		t.Errorf("%d links, want %d", len(s.links), maxLinks)
+	}
+	if _, ok := s.links[first]; ok {
+		t.Error("oldest link kept past maxLinks")
+	}
+	if hits != 1 {
+		t.Errorf("%d requests for links to one file, want 1", hits)
+	}
+
+	// Links hold no data of their own: evicting the file ends them.
+	last := s.linkOrder[len(s.linkOrder)-1]
+	s.mu.Lock()
+	s.remove(s.files[srv.URL+"/cat.mp3"])
+	s.mu.Unlock()
+	resp, err := http.Get(proxy.URL + "/audio/" + last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET link to an evicted file = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	if _, ok := ProxyURL(context.Background()); ok {
+		t.Error("ProxyURL outside HTTP mode is set")
+	}
+	if base, ok := ProxyURL(WithProxyURL(context.Background(), "http://localhost:8080/")); !ok || base != "http://localhost:8080" {
+		t.Errorf("ProxyURL = %q, %v", base, ok)
+	}
+}
@@ -0,0 +1,344 @@
+// Package cassette records Wordnik API responses to a file and replays
+// them, so that tests and demos run offline and give the same results
+// every time.
+//
+// A cassette is a JSON file of interactions, each a request and the
+// response the API gave to it. Requests are matched by method, path and
+// query; the host and the api_key parameter are ignored, so a cassette
+// recorded against the live API replays against any base URL and with any
+// key, or none. The API key and bearer token never reach the file.
+package cassette
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wordnik/mcp-server/config"
+)
+
+// Mode selects what a cassette transport does with requests.
+type Mode string
+
+const (
+	// ModeReplay serves responses from the cassette and fails requests it
+	// has no response for.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests to the API and saves the responses.
+	ModeRecord Mode = "record"
+)
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request identifies a request. URL is the full URL without the api_key
+// parameter.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Response is a recorded response. A JSON body is kept as JSON, so that
+// cassettes are readable and diff well; any other body is kept as a string.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"headers,omitempty"`
+	JSON   json.RawMessage   `json:"json,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// recordedHeaders are the response headers kept in a cassette.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+//go:embed demo.json
+var demoData []byte
+
+// Demo returns the cassette served in demo mode: a session with every tool
+// for words such as "cat" and "serendipity".
+func Demo() *Cassette {
+	c, err := Parse(demoData)
+	if err != nil {
+		panic("cassette: invalid demo cassette: " + err.Error())
+	}
+	return c
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes a cassette.
+func Parse(data []byte) (*Cassette, error) {
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	for i, in := range c.Interactions {
+		if _, err := url.Parse(in.Request.URL); err != nil || in.Request.Method == "" {
+			return nil, fmt.Errorf("interaction %d: invalid request %s %q", i, in.Request.Method, in.Request.URL)
+		}
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, replacing the file atomically.
+func (c *Cassette) Save(path string) error {
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp makes the file private; a cassette holds no secrets.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// FromConfig returns the cassette transport that cfg asks for, or nil to
+// send upstream requests over the network.
+func FromConfig(cfg *config.APIConfig) (http.RoundTripper, error) {
+	if cfg.CassetteFile == "" {
+		if cfg.Demo {
+			log.Println("Demo mode: replaying the built-in cassette")
+			return NewReplayer(Demo()), nil
+		}
+		return nil, nil
+	}
+	if cfg.CassetteMode == string(ModeRecord) {
+		if cfg.Demo {
+			return nil, errors.New("demo mode replays a cassette and cannot record one")
+		}
+		log.Printf("Recording API responses to %s", cfg.CassetteFile)
+		rec, err := NewRecorder(cfg.CassetteFile, nil)
+		if err != nil {
+			return nil, err
+		}
+		return rec, nil
+	}
+	c, err := Load(cfg.CassetteFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Replaying %d API responses from %s", len(c.Interactions), cfg.CassetteFile)
+	return NewReplayer(c), nil
+}
+
+// Transport is an http.RoundTripper that records or replays a cassette.
+// It is safe for concurrent use.
+type Transport struct {
+	mode Mode
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	index    map[string]int // key to position in cassette.Interactions
+}
+
+// NewReplayer returns a transport serving the responses in c.
+func NewReplayer(c *Cassette) *Transport {
+	return newTransport(ModeReplay, "", nil, c)
+}
+
+// NewRecorder returns a transport that sends requests through next, or
+// http.DefaultTransport if nil, and saves the responses to the cassette at
+// path after each request. Interactions already in the file are kept, and
+// replaced when the same request is made again. Rate limiting (429) and
+// server errors (5xx) are passed on but not recorded, since replaying them
+// would repeat a transient failure forever.
+func NewRecorder(path string, next http.RoundTripper) (*Transport, error) {
+	c, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		c, err = &Cassette{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return newTransport(ModeRecord, path, next, c), nil
+}
+
+func newTransport(mode Mode, path string, next http.RoundTripper, c *Cassette) *Transport {
+	t := &Transport{mode: mode, path: path, next: next, cassette: c, index: map[string]int{}}
+	for i, in := range c.Interactions {
+		if u, err := url.Parse(in.Request.URL); err == nil {
+			t.index[key(in.Request.Method, u)] = i
+		}
+	}
+	return t
+}
+
+// Len returns the number of interactions in the cassette.
+func (t *Transport) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.cassette.Interactions)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeRecord {
+		return t.record(req)
+	}
+	t.mu.Lock()
+	i, ok := t.index[key(req.Method, req.URL)]
+	var rec Response
+	if ok {
+		rec = t.cassette.Interactions[i].Response
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("cassette: no recorded response for %s %s", req.Method, scrubURL(req.URL).RequestURI())
+	}
+	return rec.httpResponse(req), nil
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return resp, nil
+	}
+
+	rec := Response{Status: resp.StatusCode, Header: map[string]string{}}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			rec.Header[h] = v
+		}
+	}
+	scrubbed := scrubBody(body, secrets(req))
+	if json.Valid(scrubbed) {
+		rec.JSON = scrubbed
+	} else {
+		rec.Body = string(scrubbed)
+	}
+	in := Interaction{Request: Request{Method: req.Method, URL: scrubURL(req.URL).String()}, Response: rec}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := key(req.Method, req.URL)
+	if i, ok := t.index[k]; ok {
+		t.cassette.Interactions[i] = in
+	} else {
+		t.index[k] = len(t.cassette.Interactions)
+		t.cassette.Interactions = append(t.cassette.Interactions, in)
+	}
+	if err := t.cassette.Save(t.path); err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	return resp, nil
+}
+
+func (r Response) httpResponse(req *http.Request) *http.Response {
+	body := []byte(r.Body)
+	if len(r.JSON) > 0 {
+		body = r.JSON
+	}
+	header := http.Header{}
+	for k, v := range r.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// key identifies a request by method, escaped path and sorted query,
+// without the api_key parameter.
+func key(method string, u *url.URL) string {
+	return method + " " + scrubURL(u).RequestURI()
+}
+
+// scrubURL returns u without the api_key parameter and with its query
+// sorted.
+func scrubURL(u *url.URL) *url.URL {
+	scrubbed := *u
+	q := u.Query()
+	q.Del("api_key")
+	scrubbed.RawQuery = q.Encode()
+	return &scrubbed
+}
+
+// secrets returns the credentials sent with req, which must not be saved.
+func secrets(req *http.Request) []string {
+	var s []string
+	if k := req.URL.Query().Get("api_key"); k != "" {
+		s = append(s, k)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		if _, token, ok := strings.Cut(auth, " "); ok && token != "" {
+			s = append(s, token)
+		}
+	}
+	// Replace longer secrets first, in case one contains another.
+	sort.Slice(s, func(i, j int) bool { return len(s[i]) > len(s[j]) })
+	return s
+}
+
+func scrubBody(body []byte, secrets []string) []byte {
+	for _, s := range secrets {
+		body = bytes.ReplaceAll(body, []byte(s), []byte("REDACTED"))
+	}
+	return body
+}
@@ -0,0 +1,199 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+const apiKey = "cassette-secret-key"
+
+func newMock(t *testing.T) (*wordnikmock.Server, string) {
+	t.Helper()
+	srv, err := wordnikmock.New(wordnikmock.Options{APIKey: apiKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, ts.URL + "/v4"
+}
+
+func get(t *testing.T, rt http.RoundTripper, target string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compact JSON, since a cassette stores it indented.
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	_, base := newMock(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec, err := NewRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := []string{
+		base + "/word.json/cat/definitions?limit=2&api_key=" + apiKey,
+		base + "/word.json/catt/definitions?api_key=" + apiKey,
+		base + "/word.json/AC%2FDC/definitions?api_key=" + apiKey,
+	}
+	want := map[string]string{}
+	for _, target := range targets {
+		status, body := get(t, rec, target)
+		want[target] = body
+		if target == targets[1] && status != http.StatusNotFound {
+			t.Errorf("recording %s: status %d, want 404", target, status)
+		}
+	}
+	// Repeating a request replaces its interaction.
+	get(t, rec, targets[0])
+	if rec.Len() != len(targets) {
+		t.Errorf("recorded %d interactions, want %d", rec.Len(), len(targets))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), apiKey) {
+		t.Errorf("cassette contains the API key:\n%s", data)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	play := NewReplayer(c)
+	for _, target := range targets {
+		// The host and the key are ignored, and the query may be in any
+		// order.
+		offline := strings.Replace(target, base, "https://api.wordnik.com/v4", 1)
+		offline = strings.Replace(offline, "api_key="+apiKey, "api_key=other", 1)
+		status, body := get(t, play, offline)
+		if body != want[target] {
+			t.Errorf("replaying %s: body %s, want %s", offline, body, want[target])
+		}
+		if target == targets[1] && status != http.StatusNotFound {
+			t.Errorf("replaying %s: status %d, want 404", offline, status)
+		}
+	}
+	if _, body := get(t, play, "http://elsewhere/v4/word.json/cat/definitions?api_key=x&limit=2"); body != want[targets[0]] {
+		t.Errorf("reordered query not matched: %s", body)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	play := NewReplayer(&Cassette{})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/v4/word.json/cat/definitions?api_key=secret", nil)
+	_, err := play.RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "no recorded response for GET /v4/word.json/cat/definitions") {
+		t.Errorf("error %v, want no recorded response", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %v contains the API key", err)
+	}
+}
+
+func TestRecordSkipsTransientFailures(t *testing.T) {
+	mock, base := newMock(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec, err := NewRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range []string{"429", "500", "503"} {
+		mock.SetFaults(wordnikmock.Fault{Kind: kind})
+		status, _ := get(t, rec, base+"/word.json/cat/definitions?api_key="+apiKey)
+		if status < 429 {
+			t.Errorf("fault %s: status %d", kind, status)
+		}
+	}
+	if rec.Len() != 0 {
+		t.Errorf("recorded %d transient failures", rec.Len())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cassette written for transient failures: %v", err)
+	}
+}
+
+func TestRecorderKeepsExistingInteractions(t *testing.T) {
+	_, base := newMock(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+	for _, word := range []string{"cat", "serendipity"} {
+		rec, err := NewRecorder(path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		get(t, rec, base+"/word.json/"+word+"/definitions?api_key="+apiKey)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 2 {
+		t.Errorf("cassette has %d interactions, want 2", len(c.Interactions))
+	}
+}
+
+func TestScrubsBearerToken(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "echo "+r.Header.Get("Authorization"))
+	}))
+	defer backend.Close()
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec, err := NewRecorder(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, backend.URL+"/v4/words.json/randomWord", nil)
+	req.Header.Set("Authorization", "Bearer token-1234")
+	resp, err := (&http.Client{Transport: rec}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Interactions[0].Response.Body; got != "echo Bearer REDACTED" {
+		t.Errorf("recorded body %q, want the token redacted", got)
+	}
+}
+
+func TestDemo(t *testing.T) {
+	play := NewReplayer(Demo())
+	if play.Len() == 0 {
+		t.Fatal("demo cassette is empty")
+	}
+	status, body := get(t, play, "https://api.wordnik.com/v4/word.json/cat/definitions?api_key=demo")
+	if status != http.StatusOK || !strings.Contains(body, "mammal") {
+		t.Errorf("demo definitions of cat: %d %s", status, body)
+	}
+}
@@ -0,0 +1,16 @@
+package client
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// Arguments returns the arguments of a tool call. The protocol lets a call
+// omit them, which is the same as passing none; arguments that are not a
+// JSON object are bad input.
+func Arguments(request mcp.CallToolRequest) (map[string]any, error) {
+	switch args := request.Params.Arguments.(type) {
+	case map[string]any:
+		return args, nil
+	case nil:
+		return map[string]any{}, nil
+	}
+	return nil, BadInput("invalid arguments object")
+}
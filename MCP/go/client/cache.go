@@ -0,0 +1,99 @@
+package client
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Forever is a cache TTL for responses that never change, such as the word
+// of the day for a past date.
+const Forever time.Duration = -1
+
+// cacheCapacity bounds the number of cached responses.
+const cacheCapacity = 4096
+
+// cache is a process-wide LRU of raw response bodies keyed by request URL
+// and a hash of the credentials, with a per-entry expiry. It also tracks the
+// requests in flight, so that concurrent identical requests share one.
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	flights map[string]*flight
+}
+
+// flight is a request in flight. done is closed once body or err is set.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+type cacheEntry struct {
+	key     string
+	body    []byte
+	expires time.Time // zero means never
+}
+
+var defaultCache = &cache{entries: make(map[string]*list.Element), order: list.New(), flights: make(map[string]*flight)}
+
+func (c *cache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.body, true
+}
+
+func (c *cache) put(key string, body []byte, ttl time.Duration) {
+	entry := &cacheEntry{key: key, body: body}
+	if ttl != Forever {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > cacheCapacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// join returns the flight of the request for key, and whether the caller
+// leads it: the leader sends the request and lands the flight, the others
+// wait for it.
+func (c *cache) join(key string) (*flight, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[key]; ok {
+		return f, false
+	}
+	f := &flight{done: make(chan struct{})}
+	c.flights[key] = f
+	return f, true
+}
+
+// land ends the flight of the request for key with its outcome.
+func (c *cache) land(key string, f *flight, body []byte, err error) {
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	f.body, f.err = body, err
+	close(f.done)
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wordnik/mcp-server/config"
+)
+
+// httpClient sends every upstream request. SetTransport replaces it.
+var httpClient = http.DefaultClient
+
+// SetTransport sends upstream requests through rt, such as a cassette
+// recorder or replayer, instead of the network. A nil rt restores the
+// default. It must be called before any request is made.
+func SetTransport(rt http.RoundTripper) {
+	if rt == nil {
+		httpClient = http.DefaultClient
+		return
+	}
+	httpClient = &http.Client{Transport: rt}
+}
+
+// Path joins URL path segments, escaping each one so that words containing
+// spaces, slashes or other reserved characters reach the API intact.
+func Path(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// QueryValue formats a tool argument as a query parameter value. Array
+// arguments are sent as the comma-separated lists the API expects.
+func QueryValue(val any) string {
+	if list, ok := val.([]any); ok {
+		parts := make([]string, len(list))
+		for i, v := range list {
+			parts[i] = fmt.Sprintf("%v", v)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// Get performs a GET request against cfg.BaseURL+path and decodes the JSON
+// response into out. Every failure is returned as an *Error.
+func Get(ctx context.Context, cfg *config.APIConfig, path string, query url.Values, out any) error {
+	_, err := fetch(ctx, cfg, path, query, out)
+	return err
+}
+
+// GetCached is Get backed by the process-wide response cache. Successful
+// responses are kept for ttl, or indefinitely if ttl is Forever. A request
+// identical to one in flight, credentials included, waits for its response
+// instead of being sent; if that request was canceled by its own caller,
+// the waiting ones try again.
+func GetCached(ctx context.Context, cfg *config.APIConfig, path string, query url.Values, ttl time.Duration, out any) error {
+	cfg = config.FromContext(ctx, cfg)
+	key := cacheKey(cfg, path, query)
+	for {
+		if body, ok := defaultCache.get(key); ok {
+			if err := json.Unmarshal(body, out); err == nil {
+				return nil
+			}
+		}
+		f, leader := defaultCache.join(key)
+		if leader {
+			body, err := fetch(ctx, cfg, path, query, out)
+			if err == nil {
+				defaultCache.put(key, body, ttl)
+			}
+			defaultCache.land(key, f, body, err)
+			return err
+		}
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return &Error{Code: CodeUpstreamUnavailable, Message: "request canceled", Err: ctx.Err()}
+		}
+		if errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded) {
+			continue
+		}
+		if f.err != nil {
+			return f.err
+		}
+		if err := json.Unmarshal(f.body, out); err != nil {
+			return &Error{Code: CodeDecodeError, Message: "unexpected response: " + bodySnippet(f.body), Err: err}
+		}
+		return nil
+	}
+}
+
+// cacheKey identifies a response in the cache: the request URL and a hash
+// of the credentials, since in HTTP mode each request brings its own and
+// one caller must not be served the responses another was authorized for.
+func cacheKey(cfg *config.APIConfig, path string, query url.Values) string {
+	creds := sha256.Sum256([]byte(cfg.APIKey + "\x00" + cfg.BearerToken + "\x00" + cfg.BasicAuth))
+	return hex.EncodeToString(creds[:8]) + " " + cfg.BaseURL + path + "?" + query.Encode()
+}
+
+// fetch sends the request, waiting for the shared rate limiter, and decodes
+// the response into out. It returns the raw body on success. A config
+// carried by ctx takes precedence over cfg.
+func fetch(ctx context.Context, cfg *config.APIConfig, path string, query url.Values, out any) ([]byte, error) {
+	cfg = config.FromContext(ctx, cfg)
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if cfg.APIKey != "" {
+		q.Set("api_key", cfg.APIKey)
+	}
+	endpoint := cfg.BaseURL + path
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, &Error{Code: CodeBadInput, Message: "failed to create request", Err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	if cfg.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.BearerToken)
+	} else if cfg.BasicAuth != "" {
+		req.Header.Set("Authorization", "Basic "+cfg.BasicAuth)
+	}
+
+	if err := defaultLimiter.wait(ctx); err != nil {
+		return nil, &Error{Code: CodeRateLimited, Message: "gave up waiting for the rate limiter", Err: err}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &Error{Code: CodeUpstreamUnavailable, Message: "request failed", Err: scrub(err, cfg.APIKey)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &Error{Code: CodeUpstreamUnavailable, Status: resp.StatusCode, Message: "failed to read response body", Err: err}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, Classify(resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return nil, &Error{
+			Code:    CodeDecodeError,
+			Status:  resp.StatusCode,
+			Message: fmt.Sprintf("unexpected %s response: %s", contentType(resp), bodySnippet(body)),
+			Err:     err,
+		}
+	}
+	return body, nil
+}
+
+func contentType(resp *http.Response) string {
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		return ct
+	}
+	return "untyped"
+}
+
+// scrub removes the API key from transport errors, which quote the full URL.
+// The error stays wrapped, so that errors.Is still finds context.Canceled.
+func scrub(err error, apiKey string) error {
+	if apiKey == "" || !strings.Contains(err.Error(), apiKey) {
+		return err
+	}
+	return &scrubbedError{err: err, apiKey: apiKey}
+}
+
+type scrubbedError struct {
+	err    error
+	apiKey string
+}
+
+func (e *scrubbedError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.apiKey, "REDACTED")
+}
+
+func (e *scrubbedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func newMock(t *testing.T, opts wordnikmock.Options) (*wordnikmock.Server, *config.APIConfig) {
+	t.Helper()
+	srv, err := wordnikmock.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, &config.APIConfig{BaseURL: ts.URL + "/v4", APIKey: opts.APIKey}
+}
+
+func TestGetClassifiesFailures(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{APIKey: "secret", Faults: []wordnikmock.Fault{
+		{Kind: "429", Pattern: "/word.json/*/frequency"},
+		{Kind: "500", Pattern: "/word.json/*/etymologies"},
+		{Kind: wordnikmock.Malformed, Pattern: "/word.json/*/examples"},
+	}})
+	tests := []struct {
+		path string
+		cfg  *config.APIConfig
+		want ErrorCode
+	}{
+		{Path("word.json", "qwxz", "definitions"), cfg, CodeNotFound},
+		{Path("word.json", "cat", "frequency"), cfg, CodeRateLimited},
+		{Path("word.json", "cat", "etymologies"), cfg, CodeUpstreamUnavailable},
+		{Path("word.json", "cat", "examples"), cfg, CodeDecodeError},
+		{Path("word.json", "cat", "definitions"), &config.APIConfig{BaseURL: cfg.BaseURL, APIKey: "wrong"}, CodeUnauthorized},
+	}
+	for _, tt := range tests {
+		var out any
+		err := Get(context.Background(), tt.cfg, tt.path, nil, &out)
+		if got := CodeOf(err); err == nil || got != tt.want {
+			t.Errorf("Get(%s) = %v, want code %s", tt.path, err, tt.want)
+		}
+		if err != nil && strings.Contains(err.Error(), "secret") {
+			t.Errorf("Get(%s) error quotes the API key: %v", tt.path, err)
+		}
+	}
+}
+
+func TestGetSendsAPIKeyAndQuery(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{APIKey: "secret"})
+	var defs []struct{ Text string }
+	query := url.Values{"limit": {"1"}}
+	if err := Get(context.Background(), cfg, Path("word.json", "cat", "definitions"), query, &defs); err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 1 || defs[0].Text == "" {
+		t.Errorf("definitions = %+v, want one", defs)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Query.Get("api_key") != "secret" || reqs[0].Query.Get("limit") != "1" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
+
+func TestGetCachedServesRepeatsFromCache(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{})
+	path := Path("word.json", "serendipity", "hyphenation")
+	for range 3 {
+		var out []map[string]any
+		if err := GetCached(context.Background(), cfg, path, nil, Forever, &out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("%d upstream requests for three cached calls, want 1", n)
+	}
+}
+
+func TestGetCachedKeepsCredentialsApart(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{APIKey: "secret"})
+	path := Path("word.json", "cat", "hyphenation")
+	var out []map[string]any
+	if err := GetCached(context.Background(), cfg, path, nil, Forever, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range []config.APIConfig{{BaseURL: cfg.BaseURL}, {BaseURL: cfg.BaseURL, APIKey: "wrong"}} {
+		if err := GetCached(context.Background(), &other, path, nil, Forever, &out); CodeOf(err) != CodeUnauthorized {
+			t.Errorf("key %q got %v, want unauthorized rather than the cached response", other.APIKey, err)
+		}
+	}
+}
+
+func TestGetCachedSharesRequestsInFlight(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{Latency: 50 * time.Millisecond})
+	path := Path("word.json", "cat", "relatedWords")
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out []map[string]any
+			errs[i] = GetCached(context.Background(), cfg, path, nil, time.Minute, &out)
+			if len(out) == 0 && errs[i] == nil {
+				errs[i] = errors.New("empty response")
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("%d upstream requests for five concurrent calls, want 1", n)
+	}
+}
+
+func TestGetCachedSharesRequestsOnlyWithTheSameCredentials(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{APIKey: "secret", Latency: 50 * time.Millisecond})
+	path := Path("word.json", "cat", "examples")
+	errs := make(chan error, 2)
+	for _, key := range []string{"secret", "wrong"} {
+		go func() {
+			var out map[string]any
+			errs <- GetCached(context.Background(), &config.APIConfig{BaseURL: cfg.BaseURL, APIKey: key}, path, nil, time.Minute, &out)
+		}()
+	}
+	var codes []ErrorCode
+	for range 2 {
+		if err := <-errs; err != nil {
+			codes = append(codes, CodeOf(err))
+		}
+	}
+	if len(codes) != 1 || codes[0] != CodeUnauthorized {
+		t.Errorf("errors %v, want one unauthorized", codes)
+	}
+}
+
+func TestGetCachedRetriesWhenTheSharedRequestIsCanceled(t *testing.T) {
+	// With an API key, transport errors are scrubbed of it and must still
+	// be recognized as the leader's deadline.
+	_, cfg := newMock(t, wordnikmock.Options{APIKey: "secret", Latency: 100 * time.Millisecond})
+	path := Path("word.json", "serendipity", "relatedWords")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	leader := make(chan error, 1)
+	go func() {
+		var out []map[string]any
+		leader <- GetCached(ctx, cfg, path, nil, time.Minute, &out)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	var out []map[string]any
+	if err := GetCached(context.Background(), cfg, path, nil, time.Minute, &out); err != nil || len(out) == 0 {
+		t.Errorf("waiting request = %v, %v; want the response despite the leader's timeout", out, err)
+	}
+	if err := <-leader; !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "secret") {
+		t.Errorf("leader = %v, want its own deadline without the API key", err)
+	}
+}
+
+func TestGetHonorsContext(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{})
+	srv.SetLatency(10 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out any
+	err := Get(ctx, cfg, Path("word.json", "cat", "frequency"), nil, &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{})
+	tests := []struct {
+		word     string
+		want     []string
+		searches []string
+	}{
+		// Close spellings missing from the longer prefix come from ^ca.
+		{"catalogs", []string{"catalog", "catalogue"}, []string{"^cata.*$", "^ca.*$"}},
+		{"catt", []string{"cat", "cats", "cart", "cattle", "car"}, []string{"^cat.*$", "^ca.*$"}},
+		{"ca", []string{"car", "cat"}, []string{"^ca.*$"}},
+		// Regular expression syntax in the word is matched literally.
+		{"c.*t", []string{}, []string{`^c\.\*.*$`, `^c\..*$`}},
+	}
+	for _, tt := range tests {
+		before := len(srv.Requests())
+		if got := Suggest(context.Background(), cfg, tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+		var searches []string
+		for _, r := range srv.Requests()[before:] {
+			q, _ := url.PathUnescape(strings.TrimPrefix(r.Path, "/words.json/search/"))
+			searches = append(searches, q)
+		}
+		if !slices.Equal(searches, tt.searches) {
+			t.Errorf("Suggest(%q) searched %q, want %q", tt.word, searches, tt.searches)
+		}
+	}
+}
+
+func TestGetWordSearchesSuggestionsOnce(t *testing.T) {
+	srv, cfg := newMock(t, wordnikmock.Options{})
+	cfg.RetryWithSuggestion = true
+	searches := func() int {
+		n := 0
+		for _, r := range srv.Requests() {
+			if strings.HasPrefix(r.Path, "/words.json/search/") {
+				n++
+			}
+		}
+		return n
+	}
+
+	// cattle is suggested but has no definitions either.
+	var defs []struct{ Text string }
+	used, err := GetWord(context.Background(), cfg, "cattl", "definitions", nil, &defs)
+	if used != "cattl" || CodeOf(err) != CodeNotFound {
+		t.Fatalf("GetWord = %q, %v, want a miss", used, err)
+	}
+	before := searches()
+	res := WordErrorResult(context.Background(), cfg, "cattl", err)
+	payload, _ := res.StructuredContent.(NoEntryPayload)
+	if !slices.Contains(payload.Suggestions, "cattle") {
+		t.Errorf("suggestions = %q, want cattle", payload.Suggestions)
+	}
+	if n := searches() - before; n != 0 {
+		t.Errorf("WordErrorResult searched %d more times", n)
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is a stable, machine-readable classification of a tool failure.
+// Codes are part of the tool contract: clients may branch on them, so existing
+// values must not be renamed.
+type ErrorCode string
+
+const (
+	CodeBadInput            ErrorCode = "bad_input"
+	CodeNotFound            ErrorCode = "not_found"
+	CodeUnauthorized        ErrorCode = "unauthorized"
+	CodeRateLimited         ErrorCode = "rate_limited"
+	CodeUpstreamUnavailable ErrorCode = "upstream_unavailable"
+	CodeDecodeError         ErrorCode = "decode_error"
+)
+
+// Error is the error type returned by every upstream call and argument check.
+type Error struct {
+	Code    ErrorCode
+	Status  int // HTTP status returned by the API, 0 if no response was received
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// BadInput reports an invalid or missing tool argument.
+func BadInput(format string, args ...any) *Error {
+	return &Error{Code: CodeBadInput, Message: fmt.Sprintf(format, args...)}
+}
+
+// CodeOf returns the classification of err, defaulting to
+// CodeUpstreamUnavailable for errors that did not originate in this package.
+func CodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeUpstreamUnavailable
+}
+
+// Classify maps a non-2xx API response to an Error.
+func Classify(status int, body []byte) *Error {
+	var code ErrorCode
+	switch {
+	case status == http.StatusNotFound:
+		code = CodeNotFound
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		code = CodeUnauthorized
+	case status == http.StatusTooManyRequests:
+		code = CodeRateLimited
+	case status >= 400 && status < 500:
+		code = CodeBadInput
+	default:
+		code = CodeUpstreamUnavailable
+	}
+	msg := fmt.Sprintf("API returned %d %s", status, http.StatusText(status))
+	if snippet := bodySnippet(body); snippet != "" {
+		msg += ": " + snippet
+	}
+	return &Error{Code: code, Status: status, Message: msg}
+}
+
+// bodySnippet returns a short single-line excerpt of body for error messages,
+// so that a multi-kilobyte HTML error page does not end up in a tool result.
+func bodySnippet(body []byte) string {
+	const max = 200
+	r := []rune(strings.Join(strings.Fields(string(body)), " "))
+	if len(r) > max {
+		return string(r[:max]) + "…"
+	}
+	return string(r)
+}
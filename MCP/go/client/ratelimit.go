@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket shared by every upstream request in the process,
+// so that concurrent tool calls cannot exceed the API quota together.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second; 0 disables limiting
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var defaultLimiter = &limiter{}
+
+// SetRateLimit limits upstream requests to rps per second, allowing bursts
+// of up to burst requests. An rps of 0 or less disables limiting.
+func SetRateLimit(rps float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	defaultLimiter.mu.Lock()
+	defer defaultLimiter.mu.Unlock()
+	defaultLimiter.rate = rps
+	defaultLimiter.burst = float64(burst)
+	defaultLimiter.tokens = float64(burst)
+	defaultLimiter.last = time.Now()
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (l *limiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// to wait before trying again.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return 0
+	}
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
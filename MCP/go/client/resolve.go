@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// Resolution describes how the dictionary interprets a possibly misspelled or
+// inflected word.
+type Resolution struct {
+	Word          string   `json:"word"`
+	CanonicalForm string   `json:"canonicalForm,omitempty"`
+	HasEntries    bool     `json:"hasEntries"`
+	Suggestions   []string `json:"suggestions"`
+}
+
+// Resolve looks word up with useCanonical enabled and reports its canonical
+// form. Suggestions are filled in when the word has no entries, or always when
+// withSuggestions is set.
+func Resolve(ctx context.Context, cfg *config.APIConfig, word string, withSuggestions bool) (Resolution, error) {
+	res := Resolution{Word: word, Suggestions: []string{}}
+	query := url.Values{}
+	query.Set("useCanonical", "true")
+	query.Set("limit", "1")
+	var defs []models.Definition
+	err := Get(ctx, cfg, Path("word.json", word, "definitions"), query, &defs)
+	if err != nil && CodeOf(err) != CodeNotFound {
+		return res, err
+	}
+	if len(defs) > 0 {
+		res.HasEntries = true
+		res.CanonicalForm = word
+		if defs[0].Word != "" {
+			res.CanonicalForm = defs[0].Word
+		}
+	}
+	if !res.HasEntries || withSuggestions {
+		if s := Suggest(ctx, cfg, word); s != nil {
+			res.Suggestions = s
+		}
+	}
+	return res, nil
+}
+
+// GetWord fetches a word-scoped resource such as "definitions" into out and
+// returns the word the results belong to. When cfg.RetryWithSuggestion is set
+// and the lookup comes back empty, it is retried once with the top spelling
+// suggestion; if that is empty too, the original outcome is returned, and a
+// not_found error carries the suggestions for WordErrorResult.
+func GetWord(ctx context.Context, cfg *config.APIConfig, word, resource string, query url.Values, out any) (string, error) {
+	err := Get(ctx, cfg, Path("word.json", word, resource), query, out)
+	if !config.FromContext(ctx, cfg).RetryWithSuggestion || !isEmpty(out, err) {
+		return word, err
+	}
+	suggestions := Suggest(ctx, cfg, word)
+	if err != nil {
+		err = &suggestedError{err, suggestions}
+	}
+	if len(suggestions) == 0 {
+		return word, err
+	}
+	retry := reflect.New(reflect.TypeOf(out).Elem())
+	retryErr := Get(ctx, cfg, Path("word.json", suggestions[0], resource), query, retry.Interface())
+	if isEmpty(retry.Interface(), retryErr) {
+		return word, err
+	}
+	reflect.ValueOf(out).Elem().Set(retry.Elem())
+	return suggestions[0], nil
+}
+
+// suggestedError is a lookup error that comes with the spelling suggestions
+// already searched for, so that a miss makes one search.
+type suggestedError struct {
+	error
+	suggestions []string
+}
+
+func (e *suggestedError) Unwrap() error {
+	return e.error
+}
+
+// isEmpty reports whether a lookup found nothing: either a not_found error
+// or a successful response that decoded to an empty value.
+func isEmpty(out any, err error) bool {
+	if err != nil {
+		return CodeOf(err) == CodeNotFound
+	}
+	v := reflect.ValueOf(out).Elem()
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// WordTextResult returns text as a tool result. If the lookup was retried
+// with a suggested spelling, a note saying so precedes the text.
+func WordTextResult(word, used, text string) *mcp.CallToolResult {
+	if used == word {
+		return mcp.NewToolResultText(text)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(fmt.Sprintf("No entry found for %q; showing results for %q.", word, used)),
+			mcp.NewTextContent(text),
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/config"
+)
+
+// ErrorPayload is the structured content attached to failed tool results.
+type ErrorPayload struct {
+	Code    ErrorCode `json:"code"`
+	Status  int       `json:"status,omitempty"`
+	Message string    `json:"message"`
+}
+
+// NoEntryPayload is the structured content of a "no entry" result.
+type NoEntryPayload struct {
+	Code        ErrorCode `json:"code"`
+	Word        string    `json:"word"`
+	Suggestions []string  `json:"suggestions"`
+}
+
+// ErrorResult converts err into an IsError tool result whose text starts with
+// the stable error code, e.g. "rate_limited: API returned 429 ...".
+func ErrorResult(err error) *mcp.CallToolResult {
+	payload := ErrorPayload{Code: CodeUpstreamUnavailable, Message: err.Error()}
+	var e *Error
+	if errors.As(err, &e) {
+		payload = ErrorPayload{Code: e.Code, Status: e.Status, Message: e.Message}
+		if e.Err != nil {
+			payload.Message = fmt.Sprintf("%s: %v", e.Message, e.Err)
+		}
+	}
+	result := mcp.NewToolResultStructured(payload, fmt.Sprintf("%s: %s", payload.Code, payload.Message))
+	result.IsError = true
+	return result
+}
+
+// WordErrorResult is ErrorResult for tools that look up a single word. A
+// not_found error is not a failure from the caller's point of view, so it is
+// reported as a regular "no entry" result carrying spelling suggestions,
+// those GetWord found if it searched already.
+func WordErrorResult(ctx context.Context, cfg *config.APIConfig, word string, err error) *mcp.CallToolResult {
+	if CodeOf(err) != CodeNotFound {
+		return ErrorResult(err)
+	}
+	var s *suggestedError
+	if errors.As(err, &s) {
+		return NoEntryResult(word, s.suggestions)
+	}
+	return NoEntryResult(word, Suggest(ctx, cfg, word))
+}
+
+// NoEntryResult reports that the dictionary has no entry for word.
+func NoEntryResult(word string, suggestions []string) *mcp.CallToolResult {
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	text := fmt.Sprintf("No entry found for %q.", word)
+	if len(suggestions) > 0 {
+		text += " Did you mean: " + strings.Join(suggestions, ", ") + "?"
+	}
+	return mcp.NewToolResultStructured(NoEntryPayload{Code: CodeNotFound, Word: word, Suggestions: suggestions}, text)
+}
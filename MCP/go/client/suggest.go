@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/internal/levenshtein"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// maxSuggestions caps the number of spelling suggestions returned.
+const maxSuggestions = 5
+
+// searchLimit caps the results of each prefix search.
+const searchLimit = 200
+
+// Suggest returns likely intended spellings of word, closest first. The API
+// has no spell-check endpoint, so candidates come from prefix searches and are
+// ranked by edit distance. A search for a prefix of up to four runes comes
+// first, since a two-rune prefix matches more words than searchLimit and
+// cuts off close spellings of long words; the two-rune prefix, which also
+// catches typos early in the word, is searched only if the first finds too
+// few. Failures yield no suggestions rather than an error, since suggestions
+// are only ever a courtesy on top of another result.
+func Suggest(ctx context.Context, cfg *config.APIConfig, word string) []string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return nil
+	}
+	runes := []rune(word)
+	maxDist := len(runes)/3 + 1
+	type candidate struct {
+		word string
+		dist int
+	}
+	seen := map[string]bool{word: true}
+	candidates := make([]candidate, 0)
+	for _, n := range prefixLengths(len(runes)) {
+		words, err := searchPrefix(ctx, cfg, string(runes[:n]))
+		if err != nil {
+			break
+		}
+		for _, w := range words {
+			lower := strings.ToLower(w)
+			if seen[lower] {
+				continue
+			}
+			seen[lower] = true
+			if d := levenshtein.Distance(word, lower); d <= maxDist {
+				candidates = append(candidates, candidate{w, d})
+			}
+		}
+		if len(candidates) >= maxSuggestions {
+			break
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].dist < candidates[j].dist
+	})
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.word
+	}
+	return suggestions
+}
+
+// prefixLengths returns the lengths, in runes, of the prefixes of a word of
+// n runes that Suggest searches, longest first. The longer prefix leaves out
+// the last rune, so that the word missing its last letter is found.
+func prefixLengths(n int) []int {
+	long, short := min(n-1, 4), min(n, 2)
+	if long > short {
+		return []int{long, short}
+	}
+	return []int{short}
+}
+
+// searchPrefix returns the words that start with prefix, ignoring case.
+func searchPrefix(ctx context.Context, cfg *config.APIConfig, prefix string) ([]string, error) {
+	query := url.Values{}
+	query.Set("allowRegex", "true")
+	query.Set("caseSensitive", "false")
+	query.Set("minDictionaryCount", "1")
+	query.Set("limit", strconv.Itoa(searchLimit))
+	var results struct {
+		Searchresults []models.WordSearchResult `json:"searchResults"`
+	}
+	if err := Get(ctx, cfg, Path("words.json", "search", "^"+regexp.QuoteMeta(prefix)+".*$"), query, &results); err != nil {
+		return nil, err
+	}
+	words := make([]string, len(results.Searchresults))
+	for i, r := range results.Searchresults {
+		words[i] = r.Word
+	}
+	return words, nil
+}
@@ -0,0 +1,175 @@
+// Command museumgeo structures the locations of the museums-json dataset
+// and exports them for mapping.
+//
+// Usage:
+//
+//	museumgeo fill [-dry-run] [-force] [dir]
+//	museumgeo geojson [-o file] [dir]
+//
+// fill resolves each file's location with the gazetteer in museums/geo and
+// adds the city, region, country (ISO 3166-1 alpha-2), lat and lon fields.
+// Fields already present are kept unless -force is given. geojson writes a
+// GeoJSON FeatureCollection of the museums that have coordinates.
+//
+// dir defaults to ../../museums-json, the dataset as seen from MCP/go.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/museums/geo"
+	"github.com/wordnik/mcp-server/museums/jsondoc"
+)
+
+const defaultDir = "../../museums-json"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "fill":
+		err = runFill(os.Args[2:])
+	case "geojson":
+		err = runGeoJSON(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "museumgeo:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: museumgeo fill [-dry-run] [-force] [dir]\n       museumgeo geojson [-o file] [dir]")
+	os.Exit(2)
+}
+
+// geoFields are the structured location fields, in the order they follow
+// location in a file.
+var geoFields = []string{"city", "region", "country", "lat", "lon"}
+
+func runFill(args []string) error {
+	fs := flag.NewFlagSet("fill", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "print the changes without writing them")
+	force := fs.Bool("force", false, "replace fields that are already set")
+	fs.Parse(args)
+	dir := defaultDir
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	g := geo.Default()
+	changed := 0
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		doc, line, err := jsondoc.Parse(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s:%d: skipped: %v\n", name, line, err)
+			continue
+		}
+		location, ok := doc.String("location")
+		if !ok || location == "" {
+			continue
+		}
+		place, err := g.Resolve(location)
+		if err != nil {
+			// What could be resolved, such as the country, is still filled.
+			fmt.Fprintf(os.Stderr, "%s:%d: location: %v\n", name, doc.Line("/location"), err)
+		}
+
+		values := map[string]any{}
+		if place.City != "" {
+			values["city"] = place.City
+		}
+		if place.Region != "" {
+			values["region"] = place.Region
+		}
+		if place.Country != "" {
+			values["country"] = place.Country
+		}
+		if place.Lat != nil && place.Lon != nil {
+			values["lat"], values["lon"] = *place.Lat, *place.Lon
+		}
+		after := []string{"location"}
+		modified := false
+		for _, key := range geoFields {
+			v, ok := values[key]
+			if ok && (*force || !doc.Has(key)) {
+				if err := doc.Set(key, v, after...); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				fmt.Printf("%s: %s = %v\n", name, key, v)
+				modified = true
+			}
+			after = append(after, key)
+		}
+		if !modified {
+			continue
+		}
+		changed++
+		if *dryRun {
+			continue
+		}
+		out, err := doc.Encode()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(name, out, 0o644); err != nil {
+			return err
+		}
+	}
+	verb := "updated"
+	if *dryRun {
+		verb = "would update"
+	}
+	fmt.Fprintf(os.Stderr, "museumgeo: %s %d of %d files\n", verb, changed, len(names))
+	return nil
+}
+
+func runGeoJSON(args []string) error {
+	fs := flag.NewFlagSet("geojson", flag.ExitOnError)
+	output := fs.String("o", "", "output file (default stdout)")
+	fs.Parse(args)
+	dir := defaultDir
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	ix, err := museums.LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	fc, skipped := museums.GeoJSON(ix.All())
+	data, err := json.MarshalIndent(fc, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0o644)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "museumgeo: %d museums mapped, %d without coordinates\n", len(fc.Features), skipped)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Command museumlint validates the museums-json dataset against its JSON
+// Schema and reports problems as file:line diagnostics.
+//
+// Usage:
+//
+//	museumlint [-fix] [-json] [-schema] [dir]
+//
+// dir defaults to ../../museums-json, the dataset as seen from MCP/go. With
+// -fix, problems marked fixable are corrected in place before linting. The
+// exit status is 1 if any error remains and 2 if the files cannot be read.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wordnik/mcp-server/museums/lint"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "normalize fixable problems in place before linting")
+	asJSON := flag.Bool("json", false, "print diagnostics as a JSON array")
+	schema := flag.Bool("schema", false, "print the JSON Schema and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: museumlint [-fix] [-json] [-schema] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *schema {
+		os.Stdout.Write(lint.SchemaJSON)
+		return
+	}
+	dir := "../../museums-json"
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	if *fix {
+		changes, err := lint.Fix(dir)
+		for _, c := range changes {
+			fmt.Fprintln(os.Stderr, "fixed", c)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "museumlint:", err)
+			os.Exit(2)
+		}
+	}
+
+	diags, err := lint.New().Dir(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "museumlint:", err)
+		os.Exit(2)
+	}
+	if *asJSON {
+		if diags == nil {
+			diags = []lint.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(diags)
+	} else {
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+	}
+	if lint.HasErrors(diags) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,135 @@
+// Command museumlist generates Museums_List.md from the museums-json
+// dataset, one section per category.
+//
+// Usage:
+//
+//	museumlist [-check] [-dir dir] [-o file]
+//
+// The paths default to the repository layout as seen from MCP/go. With
+// -check, nothing is written; the command exits with status 1 if the file
+// differs from what would be generated.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wordnik/mcp-server/museums"
+)
+
+func main() {
+	check := flag.Bool("check", false, "fail if the list is out of date instead of writing it")
+	dir := flag.String("dir", "../../museums-json", "dataset directory")
+	output := flag.String("o", "../../Museums_List.md", "markdown file to write")
+	flag.Parse()
+
+	ix, err := museums.LoadDir(*dir)
+	if err != nil {
+		fatal(err)
+	}
+	generated, err := render(ix)
+	if err != nil {
+		fatal(err)
+	}
+
+	if !*check {
+		if err := os.WriteFile(*output, generated, 0o644); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	current, err := os.ReadFile(*output)
+	if err != nil {
+		fatal(err)
+	}
+	if line, ok := firstDifference(current, generated); !ok {
+		fmt.Fprintf(os.Stderr, "%s:%d: out of date with %s; run go run ./cmd/museumlist\n", *output, line, *dir)
+		os.Exit(1)
+	}
+}
+
+// header marks the file as generated, so that edits go to the JSON files.
+const header = `# Museums List
+
+<!-- Generated from museums-json by MCP/go/cmd/museumlist. Do not edit by hand: change the JSON files and run "go run ./cmd/museumlist" in MCP/go. -->
+`
+
+// render writes every museum under the heading of its category, sorted by
+// name, each linked to its JSON file.
+func render(ix *museums.Index) ([]byte, error) {
+	known := map[string]bool{}
+	for _, c := range museums.Categories {
+		known[c.ID] = true
+	}
+	for _, m := range ix.All() {
+		if !known[m.Category] {
+			return nil, fmt.Errorf("%s: unknown category %q", m.File, m.Category)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(header)
+	for _, c := range museums.Categories {
+		list := ix.ByCategory(c.ID)
+		if len(list) == 0 {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			a, b := museums.Fold(list[i].Name), museums.Fold(list[j].Name)
+			if a != b {
+				return a < b
+			}
+			return list[i].FoundingYear() < list[j].FoundingYear()
+		})
+		fmt.Fprintf(&b, "\n## %s\n", c.Heading)
+		for _, m := range list {
+			fmt.Fprintf(&b, "\n[%s](%s)\n", entry(m), link(m))
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// entry formats a museum as "Name (year), Country", the form the list has
+// always used.
+func entry(m museums.Museum) string {
+	s := escape(m.Name)
+	if y := m.FoundingYear(); y != 0 {
+		s += fmt.Sprintf(" (%d)", y)
+	}
+	if c := m.Country(); c != "" {
+		s += ", " + escape(c)
+	}
+	return s
+}
+
+func link(m museums.Museum) string {
+	return "/museums-json/" + url.PathEscape(m.File)
+}
+
+// escape keeps brackets in names from ending the link text.
+var escape = strings.NewReplacer(`[`, `\[`, `]`, `\]`).Replace
+
+// firstDifference reports whether a and b are equal and, if not, the first
+// line on which they differ.
+func firstDifference(a, b []byte) (int, bool) {
+	if bytes.Equal(a, b) {
+		return 0, true
+	}
+	la, lb := bytes.Split(a, []byte("\n")), bytes.Split(b, []byte("\n"))
+	for i := 0; i < len(la) && i < len(lb); i++ {
+		if !bytes.Equal(la[i], lb[i]) {
+			return i + 1, false
+		}
+	}
+	return min(len(la), len(lb)) + 1, false
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "museumlist:", err)
+	os.Exit(2)
+}
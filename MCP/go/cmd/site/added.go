@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// addedDates returns when each file in dir was added to the dataset: the
+// date of the commit that first added it, following renames, or its
+// modification time when git or the history is unavailable, as for files
+// not yet committed.
+func addedDates(dir string, files []string) map[string]time.Time {
+	dates := make(map[string]time.Time, len(files))
+	_, gitErr := exec.LookPath("git")
+	for _, f := range files {
+		if gitErr == nil {
+			if t, ok := gitAdded(dir, f); ok {
+				dates[f] = t
+				continue
+			}
+		}
+		if info, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			dates[f] = info.ModTime().UTC()
+		}
+	}
+	return dates
+}
+
+func gitAdded(dir, file string) (time.Time, bool) {
+	cmd := exec.Command("git", "log", "--follow", "--diff-filter=A", "--format=%aI", "--", file)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, false
+	}
+	lines := strings.Fields(string(out))
+	if len(lines) == 0 {
+		return time.Time{}, false
+	}
+	// With --follow, the oldest addition is the last line.
+	t, err := time.Parse(time.RFC3339, lines[len(lines)-1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
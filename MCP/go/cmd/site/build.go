@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wordnik/mcp-server/museums"
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+//go:embed static
+var static embed.FS
+
+// siteData is everything the templates render.
+type siteData struct {
+	Title   string
+	Editor  string
+	BaseURL string
+	License License
+
+	Museums    []*page // sorted by name
+	Categories []categoryGroup
+	Countries  []*countryGroup // sorted by name
+}
+
+// page is one museum with what its page shows besides the record.
+type page struct {
+	museums.Detail
+	Added       time.Time
+	Category    museums.Category
+	CountrySlug string
+}
+
+type categoryGroup struct {
+	museums.Category
+	Museums []*page
+}
+
+type countryGroup struct {
+	Name    string
+	Slug    string
+	Museums []*page
+}
+
+// Path returns the location of the museum's page relative to the site root.
+func (p *page) Path() string {
+	return "museum/" + p.Slug + ".html"
+}
+
+// Summary describes the museum in one sentence, for the feed and listings.
+func (p *page) Summary() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	if p.Category.ID == museums.Planned {
+		b.WriteString(" is a planned museum")
+	} else if p.FoundingYear != 0 {
+		fmt.Fprintf(&b, " was founded in %d", p.FoundingYear)
+	} else {
+		b.WriteString(" is a language museum")
+	}
+	if p.Location != "" {
+		b.WriteString(" in " + p.Location)
+	}
+	return b.String() + "."
+}
+
+// Path returns the location of the country's page relative to the site root.
+func (c *countryGroup) Path() string {
+	return "country/" + c.Slug + ".html"
+}
+
+// loadSite reads the dataset and groups it for rendering.
+func loadSite(dir string, license License, baseURL string) (*siteData, error) {
+	ix, err := museums.LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	all := ix.All()
+	files := make([]string, len(all))
+	for i, m := range all {
+		files[i] = m.File
+	}
+	added := addedDates(dir, files)
+
+	categories := map[string]museums.Category{}
+	for _, c := range museums.Categories {
+		categories[c.ID] = c
+	}
+	s := &siteData{
+		Title:   "Language Museums of the World",
+		Editor:  "Ottar Grepstad, Centre for Norwegian Language and Literature",
+		BaseURL: baseURL,
+		License: license,
+	}
+	countries := map[string]*countryGroup{}
+	for _, m := range all {
+		p := &page{Detail: m.Detail(), Added: added[m.File], Category: categories[m.Category]}
+		s.Museums = append(s.Museums, p)
+		if p.CountryName == "" {
+			continue
+		}
+		p.CountrySlug = museums.Slugify(p.CountryName)
+		c, ok := countries[p.CountrySlug]
+		if !ok {
+			c = &countryGroup{Name: p.CountryName, Slug: p.CountrySlug}
+			countries[p.CountrySlug] = c
+			s.Countries = append(s.Countries, c)
+		}
+		c.Museums = append(c.Museums, p)
+	}
+	sort.SliceStable(s.Museums, func(i, j int) bool {
+		return museums.Fold(s.Museums[i].Name) < museums.Fold(s.Museums[j].Name)
+	})
+	sort.Slice(s.Countries, func(i, j int) bool { return s.Countries[i].Slug < s.Countries[j].Slug })
+	for _, c := range s.Countries {
+		sortByName(c.Museums)
+	}
+	for _, c := range museums.Categories {
+		group := categoryGroup{Category: c}
+		for _, p := range s.Museums {
+			if p.Category.ID == c.ID {
+				group.Museums = append(group.Museums, p)
+			}
+		}
+		if len(group.Museums) > 0 {
+			s.Categories = append(s.Categories, group)
+		}
+	}
+	return s, nil
+}
+
+func sortByName(list []*page) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return museums.Fold(list[i].Name) < museums.Fold(list[j].Name)
+	})
+}
+
+// Recent returns up to n museums, most recently added first.
+func (s *siteData) Recent(n int) []*page {
+	list := append([]*page(nil), s.Museums...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Added.After(list[j].Added) })
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// view is the data of one rendered page. Root leads from the page back to
+// the site root, so the site works from any directory or from disk.
+type view struct {
+	*siteData
+	Root      string
+	PageTitle string
+	Museum    *page
+	Country   *countryGroup
+}
+
+var funcs = template.FuncMap{
+	// weblink returns u if it is an absolute http(s) URL, so that values
+	// such as bare host names are shown as text rather than broken links.
+	"weblink": func(u string) string {
+		if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+			return u
+		}
+		return ""
+	},
+	"date": func(t time.Time) string { return t.Format("2 January 2006") },
+}
+
+// build writes the site to out. The museum and country directories are
+// replaced, so pages of removed museums do not linger. The feed is written
+// only when BaseURL is set, and a feed left by an earlier build is removed
+// otherwise.
+func (s *siteData) build(out string, feedSize int) error {
+	layout, err := template.New("layout.html").Funcs(funcs).ParseFS(templates, "templates/layout.html")
+	if err != nil {
+		return err
+	}
+	render := func(name, path string, v view) error {
+		t, err := layout.Clone()
+		if err != nil {
+			return err
+		}
+		if t, err = t.ParseFS(templates, "templates/"+name); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, "layout.html", v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return writeFile(filepath.Join(out, path), buf.Bytes())
+	}
+
+	for _, sub := range []string{"museum", "country"} {
+		if err := os.RemoveAll(filepath.Join(out, sub)); err != nil {
+			return err
+		}
+	}
+	if err := render("index.html", "index.html", view{siteData: s}); err != nil {
+		return err
+	}
+	if err := render("countries.html", "country/index.html", view{siteData: s, Root: "../", PageTitle: "Countries"}); err != nil {
+		return err
+	}
+	for _, p := range s.Museums {
+		if err := render("museum.html", p.Path(), view{siteData: s, Root: "../", PageTitle: p.Name, Museum: p}); err != nil {
+			return err
+		}
+	}
+	for _, c := range s.Countries {
+		if err := render("country.html", c.Path(), view{siteData: s, Root: "../", PageTitle: c.Name, Country: c}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.writeSearchIndex(filepath.Join(out, "search.json")); err != nil {
+		return err
+	}
+	feedPath := filepath.Join(out, "feed.xml")
+	if s.BaseURL == "" {
+		if err := os.Remove(feedPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	} else {
+		var feed bytes.Buffer
+		if err := writeFeed(&feed, s, feedSize); err != nil {
+			return err
+		}
+		if err := writeFile(feedPath, feed.Bytes()); err != nil {
+			return err
+		}
+	}
+	return fs.WalkDir(static, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := static.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return writeFile(filepath.Join(out, strings.TrimPrefix(path, "static/")), data)
+	})
+}
+
+// searchEntry is one record of search.json, read by static/search.js.
+type searchEntry struct {
+	Name     string `json:"name"`
+	Location string `json:"location,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Year     int    `json:"year,omitempty"`
+	Category string `json:"category"`
+	Path     string `json:"path"`
+}
+
+func (s *siteData) writeSearchIndex(path string) error {
+	entries := make([]searchEntry, len(s.Museums))
+	for i, p := range s.Museums {
+		entries[i] = searchEntry{
+			Name: p.Name, Location: p.Location, Country: p.CountryName, Year: p.FoundingYear,
+			Category: p.Category.Heading, Path: p.Path(),
+		}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
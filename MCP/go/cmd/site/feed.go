@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// The Atom feed is written with encoding/xml rather than a template, which
+// would need to escape for XML rather than HTML.
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Rights  string      `xml:"rights"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+// writeFeed writes the most recently added museums as an Atom feed. Entry
+// IDs are tag URIs, so they stay stable if the site moves.
+func writeFeed(w io.Writer, s *siteData, limit int) error {
+	recent := s.Recent(limit)
+	updated := time.Unix(0, 0).UTC()
+	if len(recent) > 0 {
+		updated = recent[0].Added
+	}
+	feed := atomFeed{
+		Title:   s.Title + ": recently added",
+		ID:      "tag:language-museums,2018:feed",
+		Updated: updated.Format(time.RFC3339),
+		Link: []atomLink{
+			{Href: s.BaseURL + "feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: s.BaseURL + "index.html", Rel: "alternate", Type: "text/html"},
+		},
+		Author: atomPerson{Name: s.Editor},
+		Rights: s.License.Short,
+	}
+	for _, p := range recent {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   p.Name,
+			ID:      "tag:language-museums,2018:museum/" + p.Slug,
+			Updated: p.Added.Format(time.RFC3339),
+			Link:    atomLink{Href: s.BaseURL + p.Path(), Rel: "alternate", Type: "text/html"},
+			Summary: p.Summary(),
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// License is the Creative Commons license the dataset is published under,
+// as named in the first line of license.txt.
+type License struct {
+	Name  string // "Creative Commons Attribution-ShareAlike 4.0 International"
+	Short string // "CC BY-SA 4.0"
+	URL   string // "https://creativecommons.org/licenses/by-sa/4.0/"
+}
+
+// licenseElements maps the words of a license title to the codes used in
+// its short name and URL.
+var licenseElements = []struct{ word, code string }{
+	{"Attribution", "BY"},
+	{"NonCommercial", "NC"},
+	{"NoDerivatives", "ND"},
+	{"ShareAlike", "SA"},
+}
+
+var licenseVersion = regexp.MustCompile(`\b(\d+\.\d+)\b`)
+
+// readLicense identifies the license from the title on the first non-blank
+// line of a Creative Commons legal code, e.g. "Attribution-ShareAlike 4.0
+// International".
+func readLicense(path string) (License, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return License{}, err
+	}
+	var title string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		if title = strings.TrimSpace(sc.Text()); title != "" {
+			break
+		}
+	}
+
+	var codes []string
+	for _, e := range licenseElements {
+		if strings.Contains(title, e.word) {
+			codes = append(codes, e.code)
+		}
+	}
+	version := licenseVersion.FindString(title)
+	if len(codes) == 0 || codes[0] != "BY" || version == "" {
+		return License{}, fmt.Errorf("%s: %q is not a Creative Commons license title", path, title)
+	}
+	short := strings.Join(codes, "-")
+	return License{
+		Name:  "Creative Commons " + title,
+		Short: "CC " + short + " " + version,
+		URL:   "https://creativecommons.org/licenses/" + strings.ToLower(short) + "/" + version + "/",
+	}, nil
+}
@@ -0,0 +1,54 @@
+// Command site renders the museums-json dataset into a static HTML site:
+// an index page grouped by category, a page per museum and per country, a
+// JSON index for client-side search and an Atom feed of recently added
+// museums. Everything it needs is compiled in, so it builds offline.
+//
+// Usage:
+//
+//	site [-dir dir] [-license file] [-o dir] [-base-url url] [-feed-size n]
+//
+// The paths default to the repository layout as seen from MCP/go. The feed
+// needs absolute links, so it is only written when -base-url is set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "../../museums-json", "dataset directory")
+	licensePath := flag.String("license", "../../license.txt", "license of the dataset, shown in the footer")
+	out := flag.String("o", "../../_site", "output directory")
+	baseURL := flag.String("base-url", "", "absolute URL the site is served from; the Atom feed is written only when it is set")
+	feedSize := flag.Int("feed-size", 20, "number of museums in the Atom feed")
+	flag.Parse()
+
+	if *baseURL == "" {
+		fmt.Fprintln(os.Stderr, "site: warning: -base-url is not set; skipping the Atom feed, whose links must be absolute")
+	} else if u, err := url.Parse(*baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fatal(fmt.Errorf("-base-url %q is not an absolute http(s) URL", *baseURL))
+	} else if !strings.HasSuffix(*baseURL, "/") {
+		*baseURL += "/"
+	}
+	license, err := readLicense(*licensePath)
+	if err != nil {
+		fatal(err)
+	}
+	s, err := loadSite(*dir, license, *baseURL)
+	if err != nil {
+		fatal(err)
+	}
+	if err := s.build(*out, *feedSize); err != nil {
+		fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "site: wrote %d museums and %d countries to %s\n", len(s.Museums), len(s.Countries), *out)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "site:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wordnik/mcp-server/museums"
+)
+
+func TestReadLicense(t *testing.T) {
+	tests := []struct {
+		text  string
+		want  License
+		valid bool
+	}{
+		{"Attribution-ShareAlike 4.0 International\n\n=====\n", License{
+			Name:  "Creative Commons Attribution-ShareAlike 4.0 International",
+			Short: "CC BY-SA 4.0",
+			URL:   "https://creativecommons.org/licenses/by-sa/4.0/",
+		}, true},
+		{"\n\n  Attribution-NonCommercial-NoDerivatives 4.0 International  \n", License{
+			Name:  "Creative Commons Attribution-NonCommercial-NoDerivatives 4.0 International",
+			Short: "CC BY-NC-ND 4.0",
+			URL:   "https://creativecommons.org/licenses/by-nc-nd/4.0/",
+		}, true},
+		{"Attribution 3.0 Unported\n", License{
+			Name:  "Creative Commons Attribution 3.0 Unported",
+			Short: "CC BY 3.0",
+			URL:   "https://creativecommons.org/licenses/by/3.0/",
+		}, true},
+		{"MIT License\n", License{}, false},
+		{"Attribution-ShareAlike International\n", License{}, false},
+		{"ShareAlike 4.0\n", License{}, false},
+		{"", License{}, false},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, "license.txt")
+		if err := os.WriteFile(path, []byte(tt.text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readLicense(path)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("readLicense(%q) = %+v, %v; want %+v", tt.text, got, err, tt.want)
+		}
+	}
+	if _, err := readLicense(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("missing file read")
+	}
+	if _, err := readLicense("../../../../license.txt"); err != nil {
+		t.Errorf("repository license: %v", err)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	writing := museums.Category{ID: "writing"}
+	tests := []struct {
+		m        museums.Museum
+		category museums.Category
+		want     string
+	}{
+		{museums.Museum{Name: "Gutenberg Museum", LastUpdated: "1900", Location: "Mainz, Germany"}, writing,
+			"Gutenberg Museum was founded in 1900 in Mainz, Germany."},
+		{museums.Museum{Name: "Gutenberg Museum", LastUpdated: "1900"}, writing,
+			"Gutenberg Museum was founded in 1900."},
+		{museums.Museum{Name: "Word Museum", LastUpdated: "2030", Location: "Oslo, Norway"}, museums.Category{ID: museums.Planned},
+			"Word Museum is a planned museum in Oslo, Norway."},
+		{museums.Museum{Name: "Word Museum", Location: "Oslo, Norway"}, writing,
+			"Word Museum is a language museum in Oslo, Norway."},
+	}
+	for _, tt := range tests {
+		p := &page{Detail: tt.m.Detail(), Category: tt.category}
+		if got := p.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestBuildFeed(t *testing.T) {
+	s := &siteData{
+		Title:  "Language Museums of the World",
+		Editor: "Editor",
+		Museums: []*page{{
+			Detail: museums.Museum{Slug: "gutenberg-museum", Name: "Gutenberg Museum", LastUpdated: "1900"}.Detail(),
+			Added:  time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	out := t.TempDir()
+	if err := s.build(out, 20); err != nil {
+		t.Fatal(err)
+	}
+	index, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(index), "feed.xml") {
+		t.Error("index links to the feed without a base URL")
+	}
+	feedPath := filepath.Join(out, "feed.xml")
+	if _, err := os.Stat(feedPath); err == nil {
+		t.Error("feed written without a base URL")
+	}
+
+	s.BaseURL = "https://example.org/museums/"
+	if err := s.build(out, 20); err != nil {
+		t.Fatal(err)
+	}
+	feed, err := os.ReadFile(feedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`href="https://example.org/museums/feed.xml" rel="self"`,
+		`href="https://example.org/museums/museum/gutenberg-museum.html"`,
+		"<summary>Gutenberg Museum was founded in 1900.</summary>",
+	} {
+		if !strings.Contains(string(feed), want) {
+			t.Errorf("feed lacks %s:\n%s", want, feed)
+		}
+	}
+
+	// A later build without a base URL removes the stale feed.
+	s.BaseURL = ""
+	if err := s.build(out, 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(feedPath); err == nil {
+		t.Error("stale feed kept")
+	}
+}
@@ -0,0 +1,44 @@
+// Client-side search over search.json, which cmd/site writes next to this
+// file. Matching ignores case and accents, like the museums Go package.
+(function () {
+  var input = document.getElementById("q");
+  var results = document.getElementById("results");
+  if (!input || !results) {
+    return;
+  }
+  var root = input.getAttribute("data-root") || "";
+  var entries = null;
+
+  function fold(s) {
+    return (s || "").normalize("NFD").replace(/[\u0300-\u036f]/g, "").toLowerCase();
+  }
+
+  function render(query) {
+    results.textContent = "";
+    var words = fold(query).split(/\s+/).filter(Boolean);
+    if (!entries || words.length === 0) {
+      return;
+    }
+    entries.forEach(function (e) {
+      var text = fold([e.name, e.location, e.country, e.year, e.category].join(" "));
+      if (!words.every(function (w) { return text.indexOf(w) >= 0; })) {
+        return;
+      }
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = root + e.path;
+      a.textContent = e.name;
+      li.appendChild(a);
+      li.appendChild(document.createTextNode((e.year ? " (" + e.year + ")" : "") + (e.country ? ", " + e.country : "")));
+      results.appendChild(li);
+    });
+  }
+
+  fetch(input.getAttribute("data-index"))
+    .then(function (r) { return r.json(); })
+    .then(function (data) {
+      entries = data;
+      render(input.value);
+    });
+  input.addEventListener("input", function () { render(input.value); });
+})();
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/registry"
+)
+
+// A command calls one tool. Its arguments are the tool's input schema: the
+// required properties are positional, in schema order, and every property
+// is also a flag.
+type command struct {
+	name string
+	tool models.Tool
+	// positional are the required properties. If the last is an array, it
+	// takes every remaining argument.
+	positional []string
+	// params are the properties by flag name, such as "use-canonical".
+	params map[string]param
+}
+
+// param is one property of a tool's input schema.
+type param struct {
+	name        string // property name, such as "useCanonical"
+	typ         string // JSON Schema type
+	description string
+	enum        []string
+	def         any
+}
+
+// toolPrefixes are dropped from tool names to form command names, so that
+// get_museum is "museum" and the deprecated get_word_json_word_definitions
+// is "definitions".
+var toolPrefixes = []string{"get_word_json_word_", "get_words_json_", "get_"}
+
+// commandName derives a command name from a tool name: without the API
+// path prefix and in kebab case, as in "scrabble-score".
+func commandName(tool string) string {
+	for _, p := range toolPrefixes {
+		if rest, ok := strings.CutPrefix(tool, p); ok {
+			tool = rest
+			break
+		}
+	}
+	return kebab(tool)
+}
+
+// kebab converts a camelCase or snake_case name to kebab case.
+func kebab(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_':
+			b.WriteByte('-')
+		case unicode.IsUpper(r):
+			if i > 0 && s[i-1] != '_' {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// newCommands returns a command for each tool, sorted by name.
+func newCommands(tools []models.Tool) []*command {
+	cmds := make([]*command, 0, len(tools))
+	for _, t := range tools {
+		schema := t.Definition.InputSchema
+		c := &command{name: commandName(t.Definition.Name), tool: t, params: map[string]param{}}
+		for name, raw := range schema.Properties {
+			prop, _ := raw.(map[string]any)
+			p := param{name: name, def: prop["default"]}
+			p.typ, _ = prop["type"].(string)
+			p.description, _ = prop["description"].(string)
+			if enum, ok := prop["enum"].([]string); ok {
+				p.enum = enum
+			} else if enum, ok := prop["enum"].([]any); ok {
+				for _, v := range enum {
+					p.enum = append(p.enum, fmt.Sprint(v))
+				}
+			}
+			c.params[kebab(name)] = p
+		}
+		for _, name := range schema.Required {
+			if _, ok := schema.Properties[name]; ok {
+				c.positional = append(c.positional, name)
+			}
+		}
+		cmds = append(cmds, c)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].name < cmds[j].name })
+	return cmds
+}
+
+// findCommand looks a command up by its name or its tool's name. The
+// deprecated tool names, and the commands derived from them such as
+// "definitions", also work.
+func findCommand(cmds []*command, name string) *command {
+	for _, c := range cmds {
+		if c.name == name || c.tool.Definition.Name == name {
+			return c
+		}
+	}
+	for _, c := range cmds {
+		for _, alias := range registry.Aliases(c.tool.Definition.Name) {
+			if alias == name || commandName(alias) == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// flagNames returns the command's flag names, sorted.
+func (c *command) flagNames() []string {
+	names := make([]string, 0, len(c.params))
+	for name := range c.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupFlag finds a parameter by flag name or by property name, so that
+// both --use-canonical and --useCanonical work.
+func (c *command) lookupFlag(name string) (param, bool) {
+	if p, ok := c.params[name]; ok {
+		return p, true
+	}
+	p, ok := c.params[kebab(name)]
+	return p, ok && p.name == name
+}
+
+// restArray reports whether the last positional argument takes every
+// remaining argument.
+func (c *command) restArray() bool {
+	n := len(c.positional)
+	return n > 0 && c.params[kebab(c.positional[n-1])].typ == "array"
+}
+
+// usageError is a mistake in the command line; main exits with status 2.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// parseArgs converts the command-line arguments of c into tool arguments.
+// Flags take a value, as "--limit 3" or "--limit=3", except boolean ones,
+// which may stand alone. A flag named in global, such as "format", that the
+// tool does not define is returned in globals instead. "--" ends the flags.
+func (c *command) parseArgs(args []string, global map[string]bool) (map[string]any, map[string]string, error) {
+	out := map[string]any{}
+	globals := map[string]string{}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		p, ok := c.lookupFlag(name)
+		if !ok && global[name] {
+			if !hasValue {
+				if i+1 == len(args) {
+					return nil, nil, usagef("flag --%s needs a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			globals[name] = value
+			continue
+		}
+		if !ok {
+			return nil, nil, usagef("unknown flag --%s for %s", name, c.name)
+		}
+		if !hasValue {
+			if p.typ == "boolean" {
+				value = "true"
+			} else if i+1 == len(args) {
+				return nil, nil, usagef("flag --%s needs a value", name)
+			} else {
+				i++
+				value = args[i]
+			}
+		}
+		if err := p.set(out, value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Positional arguments fill the required properties not given as flags.
+	var names []string
+	for _, name := range c.positional {
+		if _, ok := out[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for i, name := range names {
+		p := c.params[kebab(name)]
+		if i == len(positional) {
+			return nil, nil, usagef("missing argument <%s>", kebab(name))
+		}
+		if i == len(names)-1 && p.typ == "array" {
+			for _, v := range positional[i:] {
+				if err := p.set(out, v); err != nil {
+					return nil, nil, err
+				}
+			}
+			positional = positional[:i+1]
+			break
+		}
+		if err := p.set(out, positional[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(positional) > len(names) {
+		return nil, nil, usagef("unexpected argument %q", positional[len(names)])
+	}
+	return out, globals, nil
+}
+
+// set stores the flag value s in args, converted to the property's type.
+// Array values accumulate over repeated flags and may be comma-separated.
+func (p param) set(args map[string]any, s string) error {
+	if len(p.enum) > 0 && p.typ != "array" && !slices.Contains(p.enum, s) {
+		return usagef("invalid value %q for --%s: must be one of %s", s, kebab(p.name), strings.Join(p.enum, ", "))
+	}
+	switch p.typ {
+	case "number", "integer":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return usagef("invalid value %q for --%s: not a number", s, kebab(p.name))
+		}
+		args[p.name] = n
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return usagef("invalid value %q for --%s: not true or false", s, kebab(p.name))
+		}
+		args[p.name] = b
+	case "array":
+		list, _ := args[p.name].([]any)
+		for _, v := range strings.Split(s, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		args[p.name] = list
+	default:
+		args[p.name] = s
+	}
+	return nil
+}
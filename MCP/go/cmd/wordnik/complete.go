@@ -0,0 +1,114 @@
+package main
+
+import "strings"
+
+// completeCommand is the hidden command the completion scripts run to get
+// the candidates for the word being completed. Its arguments are the words
+// before it, without the program name.
+const completeCommand = "__complete"
+
+var completionScripts = map[string]string{
+	"bash": `# wordnik bash completion: source <(wordnik completion bash)
+_wordnik() {
+	local cur=${COMP_WORDS[COMP_CWORD]}
+	local IFS=$'\n'
+	COMPREPLY=($(compgen -W "$(wordnik __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}")" -- "$cur"))
+}
+complete -o default -F _wordnik wordnik
+`,
+	"zsh": `#compdef wordnik
+# wordnik zsh completion: source <(wordnik completion zsh)
+_wordnik() {
+	local -a candidates
+	candidates=(${(f)"$(wordnik __complete ${words[2,CURRENT-1]})"})
+	compadd -a candidates
+}
+compdef _wordnik wordnik
+`,
+	"fish": `# wordnik fish completion: wordnik completion fish | source
+complete -c wordnik -f -a '(wordnik __complete (commandline -opc)[2..-1])'
+`,
+}
+
+// complete returns the candidates for the word after words: command names
+// and global flags before the command, the command's flags after it, and
+// the allowed values after a flag that has them.
+func complete(cmds []*command, words []string) []string {
+	var c *command
+	var prev string
+	if len(words) > 0 {
+		prev = words[len(words)-1]
+	}
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") {
+			if c = findCommand(cmds, w); c != nil {
+				break
+			}
+			if w == "help" {
+				return commandNames(cmds)
+			}
+			if w == "completion" {
+				return []string{"bash", "fish", "zsh"}
+			}
+			return nil
+		}
+		if globalFlags[flagName(w)] && !strings.Contains(w, "=") {
+			i++ // skip the value
+		}
+	}
+
+	if strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") {
+		name := flagName(prev)
+		if c != nil {
+			if p, ok := c.lookupFlag(name); ok {
+				return p.enum
+			}
+		}
+		if name == "format" {
+			return formats
+		}
+		if globalFlags[name] {
+			return nil
+		}
+	}
+
+	if c == nil {
+		out := commandNames(cmds)
+		out = append(out, "help", "completion", "--format", "--columns")
+		return out
+	}
+	given := map[string]bool{}
+	for _, w := range words {
+		if strings.HasPrefix(w, "-") {
+			given[flagName(w)] = true
+		}
+	}
+	var out []string
+	for _, name := range c.flagNames() {
+		if !given[name] || c.params[name].typ == "array" {
+			out = append(out, "--"+name)
+		}
+	}
+	for _, name := range []string{"columns", "format"} {
+		if _, shadowed := c.params[name]; !shadowed && !given[name] {
+			out = append(out, "--"+name)
+		}
+	}
+	return out
+}
+
+func commandNames(cmds []*command) []string {
+	names := make([]string, len(cmds))
+	for i, c := range cmds {
+		names[i] = c.name
+	}
+	return names
+}
+
+// flagName returns the name of a flag argument, as "limit" for
+// "--limit=3".
+func flagName(arg string) string {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	return name
+}
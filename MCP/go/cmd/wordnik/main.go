@@ -0,0 +1,287 @@
+// Command wordnik calls the tools of the Wordnik MCP server from the
+// command line, without an MCP host. Each tool is a subcommand whose
+// arguments come from the tool's input schema: required properties are
+// positional and every property is a flag.
+//
+// Usage:
+//
+//	wordnik [--format json|markdown|table] [--columns a,b] <command> [arguments] [flags]
+//	wordnik help [command]
+//	wordnik completion bash|zsh|fish
+//
+// For example:
+//
+//	wordnik definitions serendipity --limit 3 --format markdown
+//
+// The API is configured with the server's environment variables:
+// API_BASE_URL, API_KEY, DEMO, CASSETTE_FILE and the others.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/audio"
+	"github.com/wordnik/mcp-server/cassette"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/registry"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("wordnik: ")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// globalFlags are the flags of the wordnik command itself. They go before
+// the command, or after it when the tool has no argument of the same name.
+var globalFlags = map[string]bool{"format": true, "columns": true}
+
+// options are the values of the global flags.
+type options struct {
+	format  string
+	columns []string
+}
+
+func (o *options) set(name, value string) error {
+	switch name {
+	case "format":
+		if !slices.Contains(formats, value) {
+			return usagef("invalid --format %q: must be one of %s", value, strings.Join(formats, ", "))
+		}
+		o.format = value
+	case "columns":
+		o.columns = nil
+		for _, c := range strings.Split(value, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				o.columns = append(o.columns, c)
+			}
+		}
+	}
+	return nil
+}
+
+// run runs the command line args and returns the exit status: 0 on
+// success, 1 if the tool failed and 2 for a mistake in the command line.
+func run(args []string, stdout, stderr io.Writer) int {
+	// The tool definitions do not depend on the config, so help and
+	// completion work without one.
+	cfg, cfgErr := config.LoadAPIConfig()
+	if cfg == nil {
+		cfg = &config.APIConfig{}
+	}
+	cmds := newCommands(registry.GetAll(cfg))
+
+	opts := options{format: formatJSON}
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+		if name == "h" || name == "help" {
+			printUsage(stdout, cmds)
+			return 0
+		}
+		if !globalFlags[name] {
+			return usageFailure(stderr, usagef("unknown flag %s", args[0]))
+		}
+		if !hasValue {
+			if len(args) == 1 {
+				return usageFailure(stderr, usagef("flag --%s needs a value", name))
+			}
+			value, args = args[1], args[1:]
+		}
+		if err := opts.set(name, value); err != nil {
+			return usageFailure(stderr, err)
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		printUsage(stderr, cmds)
+		return 2
+	}
+
+	switch args[0] {
+	case "help":
+		if len(args) == 1 {
+			printUsage(stdout, cmds)
+			return 0
+		}
+		c := findCommand(cmds, args[1])
+		if c == nil {
+			return usageFailure(stderr, usagef("unknown command %q", args[1]))
+		}
+		printCommandUsage(stdout, c)
+		return 0
+	case "completion":
+		if len(args) != 2 {
+			return usageFailure(stderr, usagef("usage: wordnik completion bash|zsh|fish"))
+		}
+		script, ok := completionScripts[args[1]]
+		if !ok {
+			return usageFailure(stderr, usagef("no completion for shell %q: use bash, zsh or fish", args[1]))
+		}
+		io.WriteString(stdout, script)
+		return 0
+	case completeCommand:
+		for _, s := range complete(cmds, args[1:]) {
+			fmt.Fprintln(stdout, s)
+		}
+		return 0
+	}
+
+	c := findCommand(cmds, args[0])
+	if c == nil {
+		return usageFailure(stderr, usagef("unknown command %q; run \"wordnik help\" for the list", args[0]))
+	}
+	if slices.Contains(args[1:], "-h") || slices.Contains(args[1:], "--help") {
+		printCommandUsage(stdout, c)
+		return 0
+	}
+	toolArgs, globals, err := c.parseArgs(args[1:], globalFlags)
+	if err != nil {
+		fmt.Fprintf(stderr, "wordnik %s: %v\n", c.name, err)
+		fmt.Fprintf(stderr, "Run \"wordnik help %s\" for usage.\n", c.name)
+		return 2
+	}
+	for name, value := range globals {
+		if err := opts.set(name, value); err != nil {
+			return usageFailure(stderr, err)
+		}
+	}
+
+	if cfgErr != nil {
+		fmt.Fprintf(stderr, "wordnik: %v (set DEMO=true to try the tools without an API key)\n", cfgErr)
+		return 1
+	}
+	if err := configureClient(cfg); err != nil {
+		fmt.Fprintf(stderr, "wordnik: %v\n", err)
+		return 1
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = c.tool.Definition.Name
+	req.Params.Arguments = toolArgs
+	res, err := c.tool.Handler(context.Background(), req)
+	if err != nil {
+		fmt.Fprintf(stderr, "wordnik %s: %v\n", c.name, err)
+		return 1
+	}
+	if res.IsError {
+		printResult(stderr, stderr, res, formatMarkdown, nil)
+		return 1
+	}
+	if err := printResult(stdout, stderr, res, opts.format, opts.columns); err != nil {
+		fmt.Fprintf(stderr, "wordnik %s: %v\n", c.name, err)
+		return 1
+	}
+	return 0
+}
+
+// configureClient applies the rate limit, audio and cassette settings of
+// cfg to the upstream clients, as the server does.
+func configureClient(cfg *config.APIConfig) error {
+	client.SetRateLimit(cfg.RateLimit, int(math.Ceil(cfg.RateLimit)))
+	audio.SetLimits(cfg.AudioMaxBytes, cfg.AudioTTL)
+	audio.SetHosts(cfg.AudioHosts...)
+	rt, err := cassette.FromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("loading cassette: %w", err)
+	}
+	if rt != nil {
+		client.SetTransport(rt)
+	}
+	return nil
+}
+
+func usageFailure(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "wordnik: %v\n", err)
+	var ue *usageError
+	if !errors.As(err, &ue) {
+		return 1
+	}
+	return 2
+}
+
+func printUsage(w io.Writer, cmds []*command) {
+	fmt.Fprint(w, `Usage: wordnik [--format json|markdown|table] [--columns a,b] <command> [arguments] [flags]
+
+Calls a Wordnik MCP tool and prints its result.
+
+Commands:
+`)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range cmds {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, summary(c.tool.Definition.Description))
+	}
+	tw.Flush()
+	fmt.Fprint(w, `
+Flags:
+  --format   output format: json (default), markdown or table
+  --columns  comma-separated fields to show as table or markdown columns
+
+Run "wordnik help <command>" for a command's arguments and flags, and
+"wordnik completion bash|zsh|fish" for a shell completion script.
+The API is configured with the server's environment variables, such as
+API_BASE_URL and API_KEY; DEMO=true replays recorded responses.
+`)
+}
+
+func printCommandUsage(w io.Writer, c *command) {
+	usage := "wordnik " + c.name
+	for i, name := range c.positional {
+		arg := "<" + kebab(name) + ">"
+		if i == len(c.positional)-1 && c.restArray() {
+			arg += "..."
+		}
+		usage += " " + arg
+	}
+	fmt.Fprintf(w, "Usage: %s [flags]\n\n%s\n", usage, c.tool.Definition.Description)
+	if c.tool.Definition.Name != c.name {
+		fmt.Fprintf(w, "\nTool: %s\n", c.tool.Definition.Name)
+	}
+	if len(c.params) > 0 {
+		fmt.Fprint(w, "\nFlags:\n")
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, name := range c.flagNames() {
+			p := c.params[name]
+			desc := p.description
+			if len(p.enum) > 0 {
+				desc += " (" + strings.Join(p.enum, ", ") + ")"
+			}
+			if p.def != nil {
+				desc += fmt.Sprintf(" (default %v)", p.def)
+			}
+			fmt.Fprintf(tw, "  --%s %s\t%s\n", name, p.typ, desc)
+		}
+		tw.Flush()
+	}
+	if _, ok := c.params["format"]; ok {
+		fmt.Fprint(w, "\n--format sets the tool's format; give the output format before the command.\n")
+	}
+}
+
+// summaryWidth is the longest summary in the command list.
+const summaryWidth = 72
+
+// summary returns the first sentence of a tool description, shortened to
+// summaryWidth.
+func summary(desc string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	if i := strings.Index(desc, ". "); i >= 0 {
+		desc = desc[:i]
+	}
+	desc = strings.TrimSuffix(desc, ".")
+	if len(desc) <= summaryWidth {
+		return desc
+	}
+	cut := strings.LastIndex(desc[:summaryWidth-3], " ")
+	return strings.TrimRight(desc[:cut], ",:;") + "..."
+}
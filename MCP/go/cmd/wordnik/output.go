@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output formats.
+const (
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+	formatTable    = "table"
+)
+
+var formats = []string{formatJSON, formatMarkdown, formatTable}
+
+// printResult writes a tool result to w. Text content before the last, such
+// as the note that a misspelled word was corrected, goes to notes; the last
+// is the result. JSON results are rendered in format; other text, which
+// tools write as markdown, is printed as it is.
+func printResult(w, notes io.Writer, res *mcp.CallToolResult, format string, columns []string) error {
+	var texts []string
+	for _, c := range res.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+	for i := 0; i+1 < len(texts); i++ {
+		fmt.Fprintln(notes, texts[i])
+	}
+	var text string
+	if len(texts) > 0 {
+		text = texts[len(texts)-1]
+	}
+
+	if format == formatJSON {
+		if res.StructuredContent != nil {
+			data, err := json.MarshalIndent(res.StructuredContent, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "%s\n", data)
+			return err
+		}
+		var out bytes.Buffer
+		if json.Indent(&out, []byte(text), "", "  ") != nil {
+			// Markdown or plain text: emit it as a JSON string.
+			data, _ := json.Marshal(map[string]string{"text": text})
+			return writeLine(w, string(data))
+		}
+		return writeLine(w, out.String())
+	}
+
+	v, err := decodeOrdered([]byte(text))
+	if err != nil {
+		return writeLine(w, text)
+	}
+	var out bytes.Buffer
+	if format == formatTable {
+		renderTable(&out, v, columns)
+	} else {
+		renderMarkdown(&out, v, columns)
+	}
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+func writeLine(w io.Writer, s string) error {
+	_, err := io.WriteString(w, strings.TrimRight(s, "\n")+"\n")
+	return err
+}
+
+// object is a JSON object that keeps its keys in document order, so that
+// columns appear in the order the API sends them.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// decodeOrdered decodes JSON into nil, bool, json.Number, string, []any
+// and *object values.
+func decodeOrdered(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &object{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = val
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// cell formats a value for one table cell: scalars as they are, nested
+// values as compact JSON, on one line.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	var b strings.Builder
+	writeCompact(&b, v)
+	return b.String()
+}
+
+func writeCompact(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case *object:
+		b.WriteByte('{')
+		for i, k := range v.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			b.Write(key)
+			b.WriteByte(':')
+			writeCompact(b, v.values[k])
+		}
+		b.WriteByte('}')
+	case []any:
+		b.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCompact(b, e)
+		}
+		b.WriteByte(']')
+	case json.Number:
+		b.WriteString(v.String())
+	default:
+		data, _ := json.Marshal(v)
+		b.Write(data)
+	}
+}
+
+// rows returns the objects of list and their columns: those asked for, or
+// every key that has a value in some row, in first-seen order. ok is false
+// if list holds anything but objects.
+func rows(list []any, columns []string) ([]*object, []string, bool) {
+	objs := make([]*object, 0, len(list))
+	for _, e := range list {
+		obj, isObj := e.(*object)
+		if !isObj {
+			return nil, nil, false
+		}
+		objs = append(objs, obj)
+	}
+	if len(columns) > 0 {
+		return objs, columns, true
+	}
+	seen := map[string]bool{}
+	for _, obj := range objs {
+		for _, k := range obj.keys {
+			if !seen[k] && cell(obj.values[k]) != "" {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	return objs, columns, true
+}
+
+// renderTable writes v as aligned plain-text columns: a list of objects as
+// a table with a header row, an object as key and value rows, and anything
+// else one value per line.
+func renderTable(w io.Writer, v any, columns []string) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	defer tw.Flush()
+	switch v := v.(type) {
+	case []any:
+		objs, cols, ok := rows(v, columns)
+		if !ok {
+			for _, e := range v {
+				fmt.Fprintln(tw, cell(e))
+			}
+			return
+		}
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(cols, "\t")))
+		for _, obj := range objs {
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cells[i] = cell(obj.values[c])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case *object:
+		for _, k := range v.keys {
+			fmt.Fprintf(tw, "%s\t%s\n", k, cell(v.values[k]))
+		}
+	default:
+		fmt.Fprintln(tw, cell(v))
+	}
+}
+
+// renderMarkdown writes v as markdown: a list of objects as a table, an
+// object as a list of fields, and other lists as bullet lists.
+func renderMarkdown(w io.Writer, v any, columns []string) {
+	switch v := v.(type) {
+	case []any:
+		objs, cols, ok := rows(v, columns)
+		if !ok {
+			for _, e := range v {
+				fmt.Fprintf(w, "- %s\n", cell(e))
+			}
+			return
+		}
+		if len(objs) == 0 {
+			fmt.Fprintln(w, "_No results._")
+			return
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(escapeCells(cols), " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(cols)))
+		for _, obj := range objs {
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cells[i] = cell(obj.values[c])
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(escapeCells(cells), " | "))
+		}
+	case *object:
+		for _, k := range v.keys {
+			fmt.Fprintf(w, "- **%s**: %s\n", k, cell(v.values[k]))
+		}
+	default:
+		fmt.Fprintln(w, cell(v))
+	}
+}
+
+// escapeCells keeps pipes in values from splitting markdown table cells.
+func escapeCells(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		out[i] = strings.ReplaceAll(c, "|", `\|`)
+	}
+	return out
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/registry"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func testCommands(t *testing.T) []*command {
+	t.Helper()
+	return newCommands(registry.GetAll(&config.APIConfig{}))
+}
+
+func TestCommandName(t *testing.T) {
+	tests := map[string]string{
+		"get_word_json_word_definitions":   "definitions",
+		"get_word_json_word_scrabbleScore": "scrabble-score",
+		"get_words_json_randomWords":       "random-words",
+		"get_words_json_wordOfTheDay":      "word-of-the-day",
+		"get_museum_languages":             "museum-languages",
+		"compare_word_frequency":           "compare-word-frequency",
+		"scan_text":                        "scan-text",
+		"word_audio":                       "word-audio",
+	}
+	for tool, want := range tests {
+		if got := commandName(tool); got != want {
+			t.Errorf("commandName(%q) = %q, want %q", tool, got, want)
+		}
+	}
+}
+
+func TestCommandNamesAreUnique(t *testing.T) {
+	seen := map[string]string{}
+	for _, c := range testCommands(t) {
+		if other, ok := seen[c.name]; ok {
+			t.Errorf("tools %s and %s are both %q", other, c.tool.Definition.Name, c.name)
+		}
+		seen[c.name] = c.tool.Definition.Name
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	cmds := testCommands(t)
+	tests := []struct {
+		command string
+		args    []string
+		want    map[string]any
+		globals map[string]string
+	}{
+		{"define", []string{"serendipity", "--limit", "3"}, map[string]any{"word": "serendipity", "limit": 3.0}, nil},
+		{"define", []string{"--limit=3", "-use-canonical", "true", "cats"}, map[string]any{"word": "cats", "limit": 3.0, "useCanonical": "true"}, nil},
+		{"define", []string{"--word", "cat", "--useCanonical", "false"}, map[string]any{"word": "cat", "useCanonical": "false"}, nil},
+		{"define", []string{"cat", "--source-dictionaries", "ahd-5,century", "--source-dictionaries", "wordnet"},
+			map[string]any{"word": "cat", "sourceDictionaries": []any{"ahd-5", "century", "wordnet"}}, nil},
+		{"define", []string{"cat", "--format", "markdown", "--columns=text"}, map[string]any{"word": "cat"},
+			map[string]string{"format": "markdown", "columns": "text"}},
+		{"define", []string{"--", "-ism"}, map[string]any{"word": "-ism"}, nil},
+		{"compare-word-frequency", []string{"cat", "dog", "--chart", "none"}, map[string]any{"words": []any{"cat", "dog"}, "chart": "none"}, nil},
+		{"word-of-the-day-archive", []string{"--start-date", "2024-01-01", "2024-01-07", "--format", "csv"},
+			map[string]any{"startDate": "2024-01-01", "endDate": "2024-01-07", "format": "csv"}, nil},
+		{"random-word", nil, map[string]any{}, nil},
+	}
+	for _, tt := range tests {
+		c := findCommand(cmds, tt.command)
+		got, globals, err := c.parseArgs(tt.args, globalFlags)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.command, tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %q = %v, want %v", tt.command, tt.args, got, tt.want)
+		}
+		if tt.globals == nil {
+			tt.globals = map[string]string{}
+		}
+		if !reflect.DeepEqual(globals, tt.globals) {
+			t.Errorf("%s %q globals = %v, want %v", tt.command, tt.args, globals, tt.globals)
+		}
+	}
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	cmds := testCommands(t)
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+	}{
+		{"define", nil, "missing argument <word>"},
+		{"define", []string{"cat", "dog"}, `unexpected argument "dog"`},
+		{"define", []string{"cat", "--nope"}, "unknown flag --nope"},
+		{"define", []string{"cat", "--limit"}, "flag --limit needs a value"},
+		{"define", []string{"cat", "--limit", "many"}, "not a number"},
+		{"compare-word-frequency", []string{"cat", "--chart", "pie"}, "must be one of sparkline, ascii, none"},
+	}
+	for _, tt := range tests {
+		_, _, err := findCommand(cmds, tt.command).parseArgs(tt.args, globalFlags)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %q: error %v, want %q", tt.command, tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	cmds := testCommands(t)
+	tests := []struct {
+		words []string
+		want  []string // a subset of the candidates
+		not   []string
+	}{
+		{nil, []string{"define", "scrabble-score", "help", "--format"}, []string{"--limit"}},
+		{[]string{"--format"}, []string{"json", "markdown", "table"}, []string{"define"}},
+		{[]string{"--format", "table"}, []string{"define"}, nil},
+		{[]string{"define"}, []string{"--limit", "--use-canonical", "--format", "--columns"}, []string{"define"}},
+		{[]string{"define", "cat", "--limit", "3"}, []string{"--use-canonical"}, []string{"--limit"}},
+		{[]string{"compare-word-frequency", "--chart"}, []string{"sparkline", "ascii", "none"}, []string{"--limit"}},
+		{[]string{"word-of-the-day-archive", "--format"}, []string{"json", "jsonl", "csv"}, []string{"table"}},
+		{[]string{"help"}, []string{"define"}, nil},
+		{[]string{"completion"}, []string{"bash", "zsh", "fish"}, nil},
+	}
+	for _, tt := range tests {
+		got := complete(cmds, tt.words)
+		for _, w := range tt.want {
+			if !slices.Contains(got, w) {
+				t.Errorf("complete(%q) = %q, missing %q", tt.words, got, w)
+			}
+		}
+		for _, w := range tt.not {
+			if slices.Contains(got, w) {
+				t.Errorf("complete(%q) = %q, should not offer %q", tt.words, got, w)
+			}
+		}
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	res := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent(`No entry found for "catt"; showing results for "cat".`),
+		mcp.NewTextContent(`[{"word":"cat","text":"A | B","score":1.5,"labels":[{"text":"x"}]},{"word":"cat","text":"C","score":2}]`),
+	}}
+	tests := []struct {
+		format  string
+		columns []string
+		want    string
+	}{
+		{formatJSON, nil, "[\n  {\n    \"word\": \"cat\","},
+		{formatTable, nil, "word  text   score  labels\ncat   A | B  1.5    [{\"text\":\"x\"}]\ncat   C      2\n"},
+		{formatTable, []string{"score", "word"}, "score  word\n1.5    cat\n2      cat\n"},
+		{formatMarkdown, []string{"text"}, "| text |\n| --- |\n| A \\| B |\n| C |\n"},
+	}
+	for _, tt := range tests {
+		var out, notes bytes.Buffer
+		if err := printResult(&out, &notes, res, tt.format, tt.columns); err != nil {
+			t.Fatal(err)
+		}
+		got := out.String()
+		if tt.format == formatTable {
+			got = strings.ToLower(got)
+			got = strings.Join(strings.Fields(strings.ReplaceAll(got, "\n", " \\n ")), " ")
+			want := strings.Join(strings.Fields(strings.ReplaceAll(strings.ToLower(tt.want), "\n", " \\n ")), " ")
+			if got != want {
+				t.Errorf("%s %v:\n%s\nwant\n%s", tt.format, tt.columns, out.String(), tt.want)
+			}
+		} else if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s %v:\n%s\nwant prefix\n%s", tt.format, tt.columns, got, tt.want)
+		}
+		if !strings.Contains(notes.String(), "showing results for") {
+			t.Errorf("%s: note %q not printed", tt.format, notes.String())
+		}
+	}
+
+	var out bytes.Buffer
+	markdown := &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent("| Word | Trend |\n| --- | --- |")}}
+	printResult(&out, &out, markdown, formatTable, nil)
+	if out.String() != "| Word | Trend |\n| --- | --- |\n" {
+		t.Errorf("markdown result printed as %q", out.String())
+	}
+	out.Reset()
+	printResult(&out, &out, markdown, formatJSON, nil)
+	if out.String() != `{"text":"| Word | Trend |\n| --- | --- |"}`+"\n" {
+		t.Errorf("markdown result as JSON: %q", out.String())
+	}
+}
+
+// setEnv points the configuration at the mock and clears settings that
+// change behavior.
+func setEnv(t *testing.T, baseURL string) {
+	t.Helper()
+	for _, k := range []string{"TRANSPORT", "API_KEY", "BEARER_TOKEN", "BASIC_AUTH", "DEMO", "CASSETTE_FILE", "CASSETTE_MODE", "RETRY_WITH_SUGGESTION", "MUSEUMS_DIR"} {
+		t.Setenv(k, "")
+	}
+	t.Setenv("API_BASE_URL", baseURL)
+	t.Setenv("RATE_LIMIT", "0")
+}
+
+func TestRun(t *testing.T) {
+	mock, err := wordnikmock.New(wordnikmock.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+	setEnv(t, ts.URL+"/v4")
+
+	tests := []struct {
+		args   []string
+		status int
+		stdout string
+		stderr string
+	}{
+		{[]string{"define", "cat", "--limit", "1"}, 0, `"text": "A small carnivorous mammal`, ""},
+		{[]string{"--format", "table", "define", "cat", "--columns", "partOfSpeech,text"}, 0, "PARTOFSPEECH  TEXT\nnoun", ""},
+		{[]string{"define", "serendipity", "--limit", "3", "--format", "markdown"}, 0, "| ", ""},
+		{[]string{"scrabble-score", "cat"}, 0, "5\n", ""},
+		{[]string{"get_word_json_word_scrabbleScore", "--word=cat"}, 0, "5\n", ""},
+		{[]string{"define", "catt"}, 0, `"code": "not_found"`, ""},
+		{[]string{"define"}, 2, "", "missing argument <word>"},
+		{[]string{"nonsense"}, 2, "", `unknown command "nonsense"`},
+		{[]string{"--format", "xml", "define", "cat"}, 2, "", `invalid --format "xml"`},
+		{[]string{"help"}, 0, "scrabble-score", ""},
+		{[]string{"help", "definitions"}, 0, "Usage: wordnik define <word> [flags]", ""},
+		{[]string{"definitions", "cat", "--limit", "1"}, 0, `"text": "A small carnivorous mammal`, ""},
+		{[]string{"get_word_json_word_definitions", "cat", "--limit", "1"}, 0, `"text": "A small carnivorous mammal`, ""},
+		{[]string{"compare-word-frequency", "--help"}, 0, "Usage: wordnik compare-word-frequency <words>... [flags]", ""},
+		{[]string{"completion", "bash"}, 0, "complete -o default -F _wordnik wordnik", ""},
+		{[]string{"__complete", "--format"}, 0, "json\nmarkdown\ntable\n", ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, &stdout, &stderr)
+		if status != tt.status || !strings.Contains(stdout.String(), tt.stdout) || !strings.Contains(stderr.String(), tt.stderr) {
+			t.Errorf("wordnik %q = %d\nstdout: %s\nstderr: %s\nwant %d, stdout containing %q, stderr containing %q",
+				tt.args, status, stdout.String(), stderr.String(), tt.status, tt.stdout, tt.stderr)
+		}
+	}
+
+	mock.SetFaults(wordnikmock.Fault{Kind: "500"})
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"scrabble-score", "cat"}, &stdout, &stderr); status != 1 || !strings.HasPrefix(stderr.String(), "upstream_unavailable: ") {
+		t.Errorf("failed call = %d, stderr %q", status, stderr.String())
+	}
+}
+
+func TestRunWithoutConfig(t *testing.T) {
+	setEnv(t, "")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"scrabble-score", "cat"}, &stdout, &stderr); status != 1 || !strings.Contains(stderr.String(), "API_BASE_URL") {
+		t.Errorf("call without config = %d, stderr %q", status, stderr.String())
+	}
+	stdout.Reset()
+	if status := run([]string{"help"}, &stdout, &stderr); status != 0 {
+		t.Errorf("help without config = %d", status)
+	}
+}
@@ -0,0 +1,104 @@
+// Command wordnikmock serves the Wordnik API from fixture files, for
+// developing and testing the MCP server offline.
+//
+// Usage:
+//
+//	wordnikmock [-addr 127.0.0.1:8089] [-fixtures dir] [-api-key key]
+//	            [-latency 200ms] [-fault "429 0.1"]... [-seed n]
+//
+// Point the server at it with API_BASE_URL=http://127.0.0.1:8089/v4. With
+// -api-key set, requests must carry that api_key, as API_KEY sends it.
+// -fault may be repeated; see wordnikmock.ParseFault for its syntax.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+// faultList collects repeated -fault flags.
+type faultList []wordnikmock.Fault
+
+func (l *faultList) String() string {
+	parts := make([]string, len(*l))
+	for i, f := range *l {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (l *faultList) Set(spec string) error {
+	f, err := wordnikmock.ParseFault(spec)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, f)
+	return nil
+}
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8089", "address to listen on")
+	fixtures := flag.String("fixtures", "", "fixture directory (default the fixtures compiled in)")
+	apiKey := flag.String("api-key", "", "require this api_key query parameter")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for partial faults")
+	var faults faultList
+	flag.Var(&faults, "fault", `inject failures: "kind [probability] [pattern]", kind an HTTP status or "malformed" (repeatable)`)
+	flag.Parse()
+
+	opts := wordnikmock.Options{APIKey: *apiKey, Latency: *latency, Faults: faults, Seed: *seed}
+	if *fixtures != "" {
+		opts.Fixtures = os.DirFS(*fixtures)
+	}
+	srv, err := wordnikmock.New(opts)
+	if err != nil {
+		log.Fatalf("wordnikmock: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("wordnikmock: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "wordnikmock: serving %d words; use API_BASE_URL=http://%s/v4\n", len(srv.Words()), ln.Addr())
+	if len(faults) > 0 {
+		fmt.Fprintf(os.Stderr, "wordnikmock: injecting %s\n", faults.String())
+	}
+	log.Fatal(http.Serve(ln, logRequests(srv)))
+}
+
+// logRequests logs each request with its status and duration, hiding the
+// API key.
+func logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		q := r.URL.Query()
+		if q.Has("api_key") {
+			q.Set("api_key", "REDACTED")
+		}
+		target := r.URL.Path
+		if len(q) > 0 {
+			target += "?" + q.Encode()
+		}
+		log.Printf("%s %s %d %s", r.Method, target, rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
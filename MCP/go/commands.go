@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/wotd"
+)
+
+// commands are the CLI subcommands accepted as the first program argument.
+// With no subcommand the binary runs the MCP server.
+var commands = map[string]func(cfg *config.APIConfig, args []string) error{
+	"wotd-archive": runWotdArchive,
+}
+
+// runWotdArchive exports the words of the day for a date range.
+func runWotdArchive(cfg *config.APIConfig, args []string) error {
+	fs := flag.NewFlagSet("wotd-archive", flag.ContinueOnError)
+	today := time.Now().UTC().Format(wotd.DateLayout)
+	from := fs.String("from", today, "first date (yyyy-MM-dd)")
+	to := fs.String("to", today, "last date, inclusive (yyyy-MM-dd)")
+	format := fs.String("format", wotd.FormatJSONL, "output format: json, jsonl or csv")
+	output := fs.String("o", "", "output file (default stdout)")
+	concurrency := fs.Int("concurrency", wotd.DefaultConcurrency, fmt.Sprintf("dates fetched in parallel (at most %d)", wotd.MaxConcurrency))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dates, err := wotd.ParseRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	archive := wotd.Fetch(context.Background(), cfg, dates, *concurrency)
+	for _, f := range archive.Failures {
+		log.Printf("%s: %s", f.Date, f.Error)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	bw := bufio.NewWriter(w)
+	if err := wotd.Write(bw, *format, archive.Records); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	log.Printf("Exported %d of %d dates", len(archive.Records), len(dates))
+	if len(archive.Records) == 0 && len(archive.Failures) > 0 {
+		return fmt.Errorf("all %d dates failed", len(archive.Failures))
+	}
+	return nil
+}
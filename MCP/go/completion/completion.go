@@ -0,0 +1,157 @@
+// Package completion suggests values for the arguments of prompts and
+// resource templates, answering the MCP completion/complete requests that
+// mcp-go does not handle itself. ServeStdio and Handler put it in front of
+// the STDIO and HTTP transports.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MaxValues caps the values of one completion, as the MCP specification
+// requires.
+const MaxValues = 100
+
+// Reference types of a completion request.
+const (
+	RefPrompt   = "ref/prompt"
+	RefResource = "ref/resource"
+)
+
+// A Func returns the values that complete value, best first.
+type Func func(ctx context.Context, value string) ([]string, error)
+
+// A Completer holds the completion functions of prompt and resource
+// template arguments. The zero value has none; it is safe for concurrent
+// use.
+type Completer struct {
+	mu    sync.RWMutex
+	funcs map[ref]map[string]Func
+}
+
+// ref identifies a prompt by name or a resource template by URI template.
+type ref struct {
+	typ, name string
+}
+
+// New returns an empty Completer.
+func New() *Completer {
+	return &Completer{}
+}
+
+// Prompt completes the argument arg of the prompt called name with f.
+func (c *Completer) Prompt(name, arg string, f Func) {
+	c.add(ref{RefPrompt, name}, arg, f)
+}
+
+// Resource completes the argument arg of the resource template with the
+// given URI template with f.
+func (c *Completer) Resource(uriTemplate, arg string, f Func) {
+	c.add(ref{RefResource, uriTemplate}, arg, f)
+}
+
+func (c *Completer) add(r ref, arg string, f Func) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.funcs == nil {
+		c.funcs = map[ref]map[string]Func{}
+	}
+	if c.funcs[r] == nil {
+		c.funcs[r] = map[string]Func{}
+	}
+	c.funcs[r][arg] = f
+}
+
+// Complete returns the completions of an argument of the prompt or
+// resource template that refType and refName identify. An argument
+// without completions yields no values; an unknown prompt or template is
+// an error. A failing Func is logged and yields no values too, since a
+// completion is only a courtesy while the user types.
+func (c *Completer) Complete(ctx context.Context, refType, refName, arg, value string) (*mcp.CompleteResult, error) {
+	c.mu.RLock()
+	args, ok := c.funcs[ref{refType, refName}]
+	f := args[arg]
+	c.mu.RUnlock()
+	if !ok {
+		switch refType {
+		case RefPrompt:
+			return nil, fmt.Errorf("unknown prompt %q", refName)
+		case RefResource:
+			return nil, fmt.Errorf("unknown resource template %q", refName)
+		default:
+			return nil, fmt.Errorf("unknown reference type %q", refType)
+		}
+	}
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	if f == nil {
+		return result, nil
+	}
+	values, err := f(ctx, value)
+	if err != nil {
+		log.Printf("Completing %s of %s: %v", arg, refName, err)
+		return result, nil
+	}
+	result.Completion.Total = len(values)
+	if len(values) > MaxValues {
+		values = values[:MaxValues]
+		result.Completion.HasMore = true
+	}
+	result.Completion.Values = append(result.Completion.Values, values...)
+	return result, nil
+}
+
+// Static completes from a fixed list of values, keeping those that start
+// with the value typed, ignoring case, in the order given.
+func Static(values ...string) Func {
+	return func(_ context.Context, value string) ([]string, error) {
+		return matchPrefix(values, value), nil
+	}
+}
+
+// Sorted completes from the values that list returns at completion time,
+// keeping those that start with the value typed, ignoring case, sorted.
+func Sorted(list func() []string) Func {
+	return func(_ context.Context, value string) ([]string, error) {
+		values := matchPrefix(list(), value)
+		sort.Strings(values)
+		return values, nil
+	}
+}
+
+func matchPrefix(values []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(strings.ToLower(v), prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+// EachItem completes the last item of a comma-separated list with f. The
+// values it returns are the whole list, with the items already typed.
+func EachItem(f Func) Func {
+	return func(ctx context.Context, value string) ([]string, error) {
+		i := strings.LastIndex(value, ",")
+		head, last := value[:i+1], value[i+1:]
+		trimmed := strings.TrimLeft(last, " ")
+		head += last[:len(last)-len(trimmed)]
+		values, err := f(ctx, trimmed)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = head + v
+		}
+		return values, nil
+	}
+}
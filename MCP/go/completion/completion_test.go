@@ -0,0 +1,217 @@
+package completion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+func testCompleter() *Completer {
+	c := New()
+	c.Prompt("quiz", "style", Static("multiple-choice", "fill-in-the-blank", "matching"))
+	c.Prompt("quiz", "words", EachItem(Static("cat", "cattle", "dog")))
+	c.Prompt("quiz", "audience", nil)
+	c.Prompt("broken", "word", func(context.Context, string) ([]string, error) { return nil, errors.New("upstream down") })
+	c.Resource("museum://{slug}", "slug", Sorted(func() []string { return []string{"b-museum", "a-museum", "c-house"} }))
+	var many []string
+	for i := range 150 {
+		many = append(many, fmt.Sprintf("w%03d", i))
+	}
+	c.Prompt("many", "word", Static(many...))
+	return c
+}
+
+func TestComplete(t *testing.T) {
+	c := testCompleter()
+	tests := []struct {
+		refType, name, arg, value string
+		want                      []string
+	}{
+		{RefPrompt, "quiz", "style", "M", []string{"multiple-choice", "matching"}},
+		{RefPrompt, "quiz", "style", "", []string{"multiple-choice", "fill-in-the-blank", "matching"}},
+		{RefPrompt, "quiz", "words", "dog, ca", []string{"dog, cat", "dog, cattle"}},
+		{RefPrompt, "quiz", "words", "dog,", []string{"dog,cat", "dog,cattle", "dog,dog"}},
+		{RefPrompt, "quiz", "audience", "kid", []string{}},
+		{RefPrompt, "quiz", "nope", "x", []string{}},
+		{RefPrompt, "broken", "word", "cat", []string{}},
+		{RefResource, "museum://{slug}", "slug", "", []string{"a-museum", "b-museum", "c-house"}},
+	}
+	for _, tt := range tests {
+		res, err := c.Complete(context.Background(), tt.refType, tt.name, tt.arg, tt.value)
+		if err != nil {
+			t.Errorf("%s %s %q: %v", tt.name, tt.arg, tt.value, err)
+			continue
+		}
+		if got := res.Completion.Values; !slices.Equal(got, tt.want) {
+			t.Errorf("%s %s %q = %q, want %q", tt.name, tt.arg, tt.value, got, tt.want)
+		}
+	}
+
+	res, err := c.Complete(context.Background(), RefPrompt, "many", "word", "w")
+	if err != nil || len(res.Completion.Values) != MaxValues || res.Completion.Total != 150 || !res.Completion.HasMore {
+		t.Errorf("many = %d values, total %d, more %v, %v", len(res.Completion.Values), res.Completion.Total, res.Completion.HasMore, err)
+	}
+	for _, ref := range [][2]string{{RefPrompt, "nope"}, {RefResource, "nope://{x}"}, {"ref/other", "quiz"}} {
+		if _, err := c.Complete(context.Background(), ref[0], ref[1], "style", ""); err == nil {
+			t.Errorf("completing %v succeeded", ref)
+		}
+	}
+}
+
+// rpc is a JSON-RPC response as a client decodes it.
+type rpc struct {
+	ID     int `json:"id"`
+	Result struct {
+		Capabilities map[string]any `json:"capabilities"`
+		Completion   struct {
+			Values []string `json:"values"`
+		} `json:"completion"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+const (
+	initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`
+	completeRequest   = `{"jsonrpc":"2.0","id":2,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"quiz"},"argument":{"name":"style","value":"f"}}}`
+	unknownRequest    = `{"jsonrpc":"2.0","id":3,"method":"completion/complete","params":{"ref":{"type":"ref/resource","uri":"nope://{x}"},"argument":{"name":"x","value":""}}}`
+	pingRequest       = `{"jsonrpc":"2.0","id":4,"method":"ping"}`
+)
+
+func checkResponses(t *testing.T, responses map[int]rpc) {
+	t.Helper()
+	if _, ok := responses[1].Result.Capabilities["completions"]; !ok {
+		t.Errorf("initialize capabilities %v lack completions", responses[1].Result.Capabilities)
+	}
+	if _, ok := responses[1].Result.Capabilities["tools"]; !ok {
+		t.Errorf("initialize capabilities %v lost tools", responses[1].Result.Capabilities)
+	}
+	if got := responses[2].Result.Completion.Values; !slices.Equal(got, []string{"fill-in-the-blank"}) {
+		t.Errorf("completion = %q", got)
+	}
+	if e := responses[3].Error; e == nil || !strings.Contains(e.Message, "unknown resource template") {
+		t.Errorf("unknown template error = %+v", e)
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	s := server.NewMCPServer("test", "1", server.WithToolCapabilities(false))
+	in := strings.NewReader(strings.Join([]string{initializeRequest, completeRequest, unknownRequest, pingRequest}, "\n") + "\n")
+	var out bytes.Buffer
+	if err := testCompleter().ServeStdio(context.Background(), s, in, &out); err != nil {
+		t.Fatal(err)
+	}
+	responses := map[int]rpc{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var r rpc
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		responses[r.ID] = r
+	}
+	if len(responses) != 4 {
+		t.Errorf("got %d responses, want 4:\n%s", len(responses), out.String())
+	}
+	checkResponses(t, responses)
+}
+
+func TestHandler(t *testing.T) {
+	s := server.NewMCPServer("test", "1", server.WithToolCapabilities(false))
+	type key struct{}
+	var seen []string
+	contextFunc := func(ctx context.Context, r *http.Request) context.Context {
+		return context.WithValue(ctx, key{}, r.Header.Get("X-Test"))
+	}
+	c := testCompleter()
+	c.Prompt("header", "value", func(ctx context.Context, _ string) ([]string, error) {
+		v, _ := ctx.Value(key{}).(string)
+		seen = append(seen, v)
+		return []string{v}, nil
+	})
+	srv := httptest.NewServer(c.Handler(server.NewStreamableHTTPServer(s), contextFunc))
+	defer srv.Close()
+
+	post := func(body string) (rpc, http.Header) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set("X-Test", "from-header")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var r rpc
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			t.Fatalf("%s: %v", body, err)
+		}
+		return r, resp.Header
+	}
+	responses := map[int]rpc{}
+	var header http.Header
+	responses[1], header = post(initializeRequest)
+	if header.Get(server.HeaderKeySessionID) == "" {
+		t.Errorf("initialize response lost the session header")
+	}
+	responses[2], _ = post(completeRequest)
+	responses[3], _ = post(unknownRequest)
+	checkResponses(t, responses)
+
+	r, _ := post(`{"jsonrpc":"2.0","id":5,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"header"},"argument":{"name":"value","value":""}}}`)
+	if !slices.Equal(r.Result.Completion.Values, []string{"from-header"}) || !slices.Equal(seen, []string{"from-header"}) {
+		t.Errorf("completion context = %q, seen %q", r.Result.Completion.Values, seen)
+	}
+}
+
+func TestMuseumValues(t *testing.T) {
+	store := museums.DefaultStore()
+	slugs, _ := MuseumSlugs(store, func(uri string) bool { return uri != "museum://alutiiq-museum" })(context.Background(), "alu")
+	if slices.Contains(slugs, "alutiiq-museum") {
+		t.Errorf("slugs %q include a museum that is not served", slugs)
+	}
+	if all, _ := MuseumSlugs(store, nil)(context.Background(), "ALU"); !slices.Contains(all, "alutiiq-museum") {
+		t.Errorf("slugs %q lack alutiiq-museum", all)
+	}
+	countries, _ := MuseumCountries(store)(context.Background(), "s")
+	for _, want := range []string{"Spain", "Switzerland", "South Africa"} {
+		if !slices.Contains(countries, want) {
+			t.Errorf("countries %q lack %s", countries, want)
+		}
+	}
+}
+
+func TestWordsCached(t *testing.T) {
+	var queries []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"searchResults":[{"word":"serendipity"},{"word":"serene"}],"totalResults":2}`)
+	}))
+	defer api.Close()
+	words := Words(&config.APIConfig{BaseURL: api.URL, APIKey: "key"})
+	for range 3 {
+		got, err := words(context.Background(), "sere")
+		if err != nil || !slices.Equal(got, []string{"serendipity", "serene"}) {
+			t.Fatalf("words = %q, %v", got, err)
+		}
+	}
+	if got, _ := words(context.Background(), " "); got != nil {
+		t.Errorf("blank prefix = %q", got)
+	}
+	if len(queries) != 1 || queries[0] != "/words.json/search/^sere.*$" {
+		t.Errorf("queries = %q, want one search for ^sere.*$", queries)
+	}
+}
@@ -0,0 +1,217 @@
+package completion
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodComplete is the JSON-RPC method of completion requests.
+const methodComplete = "completion/complete"
+
+// request is the part of a JSON-RPC message that decides whether the
+// Completer answers it.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  struct {
+		Ref struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+			URI  string `json:"uri"`
+		} `json:"ref"`
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+	} `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// parse returns msg as a completion request, or false for any other
+// message.
+func parse(msg []byte) (*request, bool) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil || req.Method != methodComplete || len(req.ID) == 0 {
+		return nil, false
+	}
+	return &req, true
+}
+
+// answer returns the response to a completion request.
+func (c *Completer) answer(ctx context.Context, req *request) []byte {
+	resp := response{JSONRPC: mcp.JSONRPC_VERSION, ID: req.ID}
+	name := req.Params.Ref.Name
+	if req.Params.Ref.Type == RefResource {
+		name = req.Params.Ref.URI
+	}
+	result, err := c.Complete(ctx, req.Params.Ref.Type, name, req.Params.Argument.Name, req.Params.Argument.Value)
+	if err != nil {
+		resp.Error = &responseError{Code: mcp.INVALID_PARAMS, Message: err.Error()}
+	} else {
+		resp.Result = result
+	}
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+// declare adds the completions capability to msg if it is the result of
+// an initialize request, which mcp-go cannot declare. Any other message is
+// returned unchanged.
+func declare(msg []byte) []byte {
+	if !bytes.Contains(msg, []byte(`"protocolVersion"`)) {
+		return msg
+	}
+	var full, result, caps map[string]json.RawMessage
+	if json.Unmarshal(msg, &full) != nil || json.Unmarshal(full["result"], &result) != nil ||
+		result["protocolVersion"] == nil || json.Unmarshal(result["capabilities"], &caps) != nil {
+		return msg
+	}
+	if caps == nil {
+		caps = map[string]json.RawMessage{}
+	}
+	caps["completions"] = json.RawMessage("{}")
+	result["capabilities"], _ = json.Marshal(caps)
+	full["result"], _ = json.Marshal(result)
+	data, err := json.Marshal(full)
+	if err != nil {
+		return msg
+	}
+	return data
+}
+
+// ServeStdio serves s over in and out as server.ServeStdio does, until in
+// is closed or ctx is canceled. Completion requests are answered by c
+// instead of s.
+func (c *Completer) ServeStdio(ctx context.Context, s *server.MCPServer, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := &lineWriter{w: out}
+	pr, pw := io.Pipe()
+	var pending sync.WaitGroup
+	defer pending.Wait()
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				if req, ok := parse(line); ok {
+					// Word completions wait for the API, so they must not
+					// hold up the messages that follow.
+					pending.Add(1)
+					go func() {
+						defer pending.Done()
+						w.Write(c.answer(ctx, req))
+					}()
+				} else if _, err := pw.Write(line); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				pw.CloseWithError(ignoreEOF(err))
+				return
+			}
+		}
+	}()
+	return server.NewStdioServer(s).Listen(ctx, pr, w)
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// lineWriter serializes the messages written to the STDIO output, one
+// line per Write, and declares the completions capability.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	msg := declare(bytes.TrimRight(p, "\n"))
+	if _, err := w.w.Write(append(msg, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Handler returns next with completion requests answered by c. contextFunc
+// prepares the context of each completion from its request, as it does
+// for next with server.WithHTTPContextFunc; it may be nil.
+func (c *Completer) Handler(next http.Handler, contextFunc server.HTTPContextFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if req, ok := parse(body); ok {
+			ctx := r.Context()
+			if contextFunc != nil {
+				ctx = contextFunc(ctx, r)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write(append(c.answer(ctx, req), '\n'))
+			return
+		}
+
+		var req request
+		if json.Unmarshal(body, &req) != nil || req.Method != string(mcp.MethodInitialize) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// The initialize result is small and always plain JSON, so it is
+		// buffered to declare the capability.
+		rec := &recorder{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		data := rec.body.Bytes()
+		if rec.status == http.StatusOK {
+			data = append(declare(bytes.TrimRight(data, "\n")), '\n')
+		}
+		w.WriteHeader(rec.status)
+		w.Write(data)
+	})
+}
+
+// recorder buffers a response, sharing the header of the real one.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) WriteHeader(status int)      { r.status = status }
+func (r *recorder) Write(p []byte) (int, error) { return r.body.Write(p) }
@@ -0,0 +1,83 @@
+package completion
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/resources"
+)
+
+const (
+	// wordLimit is the number of words a word completion asks the API for.
+	wordLimit = 20
+	// wordTTL is how long the words for a prefix are cached. Completions
+	// repeat the same searches as the user types and deletes.
+	wordTTL = time.Hour
+)
+
+// Words completes word prefixes with a word search, as the search_words
+// tool does, against the API that cfg or the request context configures.
+// Results are cached per API and prefix. An empty prefix has no
+// completions.
+func Words(cfg *config.APIConfig) Func {
+	return func(ctx context.Context, prefix string) ([]string, error) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			return nil, nil
+		}
+		query := url.Values{}
+		query.Set("allowRegex", "true")
+		query.Set("caseSensitive", "false")
+		query.Set("limit", strconv.Itoa(wordLimit))
+		var results struct {
+			Searchresults []models.WordSearchResult `json:"searchResults"`
+		}
+		path := client.Path("words.json", "search", "^"+regexp.QuoteMeta(prefix)+".*$")
+		if err := client.GetCached(ctx, cfg, path, query, wordTTL, &results); err != nil {
+			return nil, err
+		}
+		words := make([]string, 0, len(results.Searchresults))
+		for _, r := range results.Searchresults {
+			words = append(words, r.Word)
+		}
+		return words, nil
+	}
+}
+
+// MuseumSlugs completes museum slugs from the museums in store at the time
+// of the request, so that reloads are reflected. Only the museums whose
+// resource URI allow accepts are offered; a nil allow offers every one.
+func MuseumSlugs(store *museums.Store, allow func(uri string) bool) Func {
+	return Sorted(func() []string {
+		var slugs []string
+		for _, m := range store.Index().All() {
+			if allow == nil || allow(resources.MuseumURI(m.Slug)) {
+				slugs = append(slugs, m.Slug)
+			}
+		}
+		return slugs
+	})
+}
+
+// MuseumCountries completes the countries of the museums in store. The
+// prefix matches regardless of case and accents.
+func MuseumCountries(store *museums.Store) Func {
+	return func(_ context.Context, prefix string) ([]string, error) {
+		prefix = museums.Fold(prefix)
+		var countries []string
+		for _, c := range store.Index().Countries() {
+			if strings.HasPrefix(museums.Fold(c), prefix) {
+				countries = append(countries, c)
+			}
+		}
+		return countries, nil
+	}
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type APIConfig struct {
@@ -11,6 +16,101 @@ type APIConfig struct {
 	APIKey      string // For API key authentication
 	BasicAuth   string // For basic authentication
 	Port        string // For server port configuration
+
+	// RetryWithSuggestion makes word tools retry an empty lookup once with the
+	// top spelling suggestion instead of returning no results.
+	RetryWithSuggestion bool
+
+	// RateLimit caps upstream requests per second across all tool calls.
+	// Zero disables the limit.
+	RateLimit float64
+
+	// CORSAllowedOrigins is the comma-separated list of origins allowed to
+	// call the museums REST API in HTTP/HTTPS mode, or "*" for any.
+	CORSAllowedOrigins string
+
+	// MuseumsDir, if set, is a museums-json directory served instead of the
+	// embedded dataset and reloaded when its files change.
+	MuseumsDir string
+	// MuseumsWatchInterval is how often MuseumsDir is checked for changes.
+	// Zero loads it once without watching.
+	MuseumsWatchInterval time.Duration
+
+	// CassetteFile, if set, is a cassette of recorded API responses,
+	// replayed or recorded according to CassetteMode.
+	CassetteFile string
+	// CassetteMode is "replay" (the default) or "record".
+	CassetteMode string
+	// Demo replays CassetteFile, or the built-in demo cassette if it is
+	// unset, so that the server runs without network access, an API key
+	// or API_BASE_URL.
+	Demo bool
+
+	// Tools are the patterns selecting the tools and resources served, from
+	// TOOLS_PROFILE, TOOLS_FILE and TOOLS in that order. Empty serves
+	// everything. The registry package defines their syntax.
+	Tools []string
+
+	// AudioMaxBytes is the size limit of one audio file the word_audio tool
+	// downloads.
+	AudioMaxBytes int64
+	// AudioTTL is how long downloaded audio files are cached and served
+	// through the /audio/{id} proxy in HTTP/HTTPS mode.
+	AudioTTL time.Duration
+	// AudioHosts are the hosts, with their subdomains, that the word_audio
+	// tool downloads files from: AUDIO_HOSTS, or DefaultAudioHosts, plus the
+	// host of API_BASE_URL if set in the environment.
+	AudioHosts []string
+	// AudioPublicURL, if set, is the base URL that audio proxy links use
+	// instead of the scheme and host of the request, for servers behind a
+	// reverse proxy.
+	AudioPublicURL string
+}
+
+type contextKey struct{}
+
+// WithContext returns a context carrying a request-scoped config, such as the
+// one built from HTTP headers in HTTP/HTTPS mode.
+func WithContext(ctx context.Context, cfg *APIConfig) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext returns the request-scoped config carried by ctx, or fallback
+// if there is none.
+func FromContext(ctx context.Context, fallback *APIConfig) *APIConfig {
+	if cfg, ok := ctx.Value(contextKey{}).(*APIConfig); ok && cfg != nil {
+		return cfg
+	}
+	return fallback
+}
+
+// DefaultRateLimit is used when RATE_LIMIT is not set.
+const DefaultRateLimit = 5
+
+// DefaultMuseumsWatchInterval is used when MUSEUMS_WATCH_INTERVAL is not set.
+const DefaultMuseumsWatchInterval = 2 * time.Second
+
+// DefaultAudioMaxBytes is used when AUDIO_MAX_BYTES is not set.
+const DefaultAudioMaxBytes = 2 << 20
+
+// DefaultAudioTTL is used when AUDIO_TTL is not set.
+const DefaultAudioTTL = 10 * time.Minute
+
+// DefaultAudioHosts are used when AUDIO_HOSTS is not set.
+var DefaultAudioHosts = []string{"wordnik.com"}
+
+// DefaultBaseURL is the Wordnik API, used in demo mode when API_BASE_URL
+// is not set.
+const DefaultBaseURL = "https://api.wordnik.com/v4"
+
+// DefaultCORSAllowedOrigins is used when CORS_ALLOWED_ORIGINS is not set.
+const DefaultCORSAllowedOrigins = "*"
+
+// ParseBool interprets an optional boolean setting; unset or malformed values
+// are false.
+func ParseBool(value string) bool {
+	b, _ := strconv.ParseBool(value)
+	return b
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
@@ -28,6 +128,11 @@ func LoadAPIConfig() (*APIConfig, error) {
 		transport = os.Getenv("transport")
 	}
 	
+	demo := ParseBool(os.Getenv("DEMO"))
+	if demo && baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL is required from environment
 	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && baseURL == "" {
 		return nil, fmt.Errorf("API_BASE_URL environment variable not set")
@@ -36,13 +141,125 @@ func LoadAPIConfig() (*APIConfig, error) {
 	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
+	rateLimit := float64(DefaultRateLimit)
+	if v := os.Getenv("RATE_LIMIT"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT %q: must be a non-negative number of requests per second", v)
+		}
+		rateLimit = parsed
+	}
+
+	watchInterval := DefaultMuseumsWatchInterval
+	if v := os.Getenv("MUSEUMS_WATCH_INTERVAL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid MUSEUMS_WATCH_INTERVAL %q: must be a non-negative duration such as 2s", v)
+		}
+		watchInterval = parsed
+	}
+
+	audioMaxBytes := int64(DefaultAudioMaxBytes)
+	if v := os.Getenv("AUDIO_MAX_BYTES"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid AUDIO_MAX_BYTES %q: must be a positive number of bytes", v)
+		}
+		audioMaxBytes = parsed
+	}
+
+	audioTTL := DefaultAudioTTL
+	if v := os.Getenv("AUDIO_TTL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid AUDIO_TTL %q: must be a positive duration such as 10m", v)
+		}
+		audioTTL = parsed
+	}
+
+	audioHosts := DefaultAudioHosts
+	if v, ok := os.LookupEnv("AUDIO_HOSTS"); ok {
+		audioHosts = splitList(v)
+	}
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		audioHosts = append(audioHosts[:len(audioHosts):len(audioHosts)], u.Hostname())
+	}
+
+	tools, err := toolPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	cassetteMode := os.Getenv("CASSETTE_MODE")
+	if cassetteMode == "" {
+		cassetteMode = "replay"
+	}
+	if cassetteMode != "replay" && cassetteMode != "record" {
+		return nil, fmt.Errorf("invalid CASSETTE_MODE %q: must be replay or record", cassetteMode)
+	}
+
+	corsOrigins, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS")
+	if !ok {
+		corsOrigins = DefaultCORSAllowedOrigins
+	}
+
 	return &APIConfig{
 		BaseURL:     baseURL,
 		BearerToken: os.Getenv("BEARER_TOKEN"),
 		APIKey:      os.Getenv("API_KEY"),
 		BasicAuth:   os.Getenv("BASIC_AUTH"),
 		Port:        port,
+
+		RetryWithSuggestion: ParseBool(os.Getenv("RETRY_WITH_SUGGESTION")),
+		RateLimit:           rateLimit,
+		CORSAllowedOrigins:  corsOrigins,
+
+		MuseumsDir:           os.Getenv("MUSEUMS_DIR"),
+		MuseumsWatchInterval: watchInterval,
+
+		CassetteFile: os.Getenv("CASSETTE_FILE"),
+		CassetteMode: cassetteMode,
+		Demo:         demo,
+
+		Tools: tools,
+
+		AudioMaxBytes:  audioMaxBytes,
+		AudioHosts:     audioHosts,
+		AudioTTL:       audioTTL,
+		AudioPublicURL: strings.TrimSuffix(os.Getenv("AUDIO_PUBLIC_URL"), "/"),
 	}, nil
 }
 
+// toolPatterns collects the tool selection patterns: TOOLS_PROFILE as
+// "@profile", then the patterns in TOOLS_FILE, one or more per line with
+// "#" starting a comment, then the comma-separated TOOLS.
+func toolPatterns() ([]string, error) {
+	var patterns []string
+	if profile := strings.TrimSpace(os.Getenv("TOOLS_PROFILE")); profile != "" {
+		patterns = append(patterns, "@"+profile)
+	}
+	if file := os.Getenv("TOOLS_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading TOOLS_FILE: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line, _, _ = strings.Cut(line, "#")
+			patterns = append(patterns, splitList(line)...)
+		}
+	}
+	return append(patterns, splitList(os.Getenv("TOOLS"))...), nil
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 
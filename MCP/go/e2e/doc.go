@@ -0,0 +1,9 @@
+// Package e2e holds the end-to-end tests of the MCP server. They build the
+// server binary, run it over STDIO, HTTP and HTTPS against a wordnikmock
+// server, and drive it with an MCP client: initialize, tools/list, and a
+// tools/call of every tool, including their error results. Demo mode is
+// checked against the built-in cassette. The tests need
+// the go command to build the binary; -short skips them.
+//
+//	go test ./e2e
+package e2e
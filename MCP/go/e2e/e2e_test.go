@@ -0,0 +1,849 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+// result is a tools/call result with helpers for the checks.
+type result struct {
+	*mcp.CallToolResult
+	text string
+}
+
+// json decodes the text content, which is JSON for most tools.
+func (r result) json(t *testing.T, v any) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(r.text), v); err != nil {
+		t.Fatalf("text content is not the expected JSON: %v\n%s", err, r.text)
+	}
+}
+
+// structured decodes the structured content.
+func (r result) structured(t *testing.T, v any) {
+	t.Helper()
+	data, err := json.Marshal(r.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.StructuredContent == nil {
+		t.Fatalf("no structured content; text: %s", r.text)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("structured content is not the expected shape: %v\n%s", err, data)
+	}
+}
+
+type definition struct {
+	Word         string `json:"word"`
+	Text         string `json:"text"`
+	PartOfSpeech string `json:"partOfSpeech"`
+}
+
+type errorPayload struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Word        string   `json:"word"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// toolCall is one tools/call and the checks on its result. Every tool in
+// tools/list needs at least one call that succeeds.
+type toolCall struct {
+	name  string
+	tool  string
+	args  map[string]any
+	check func(t *testing.T, r result)
+}
+
+var toolCalls = []toolCall{
+	{"definitions", "define", map[string]any{"word": "cat", "limit": 2}, func(t *testing.T, r result) {
+		var defs []definition
+		r.json(t, &defs)
+		if len(defs) != 2 || defs[0].Word != "cat" || defs[0].Text == "" {
+			t.Errorf("definitions = %+v, want two for cat", defs)
+		}
+	}},
+	{"definitions of a word with a slash", "define", map[string]any{"word": "AC/DC"}, func(t *testing.T, r result) {
+		var defs []definition
+		r.json(t, &defs)
+		if len(defs) != 1 || defs[0].Word != "AC/DC" {
+			t.Errorf("definitions = %+v, want the AC/DC entry", defs)
+		}
+	}},
+	{"definitions of an unknown word", "define", map[string]any{"word": "catt"}, func(t *testing.T, r result) {
+		var p errorPayload
+		r.structured(t, &p)
+		if p.Code != "not_found" || p.Word != "catt" || !slices.Contains(p.Suggestions, "cat") {
+			t.Errorf("no-entry payload = %+v, want not_found suggesting cat", p)
+		}
+	}},
+	{"phrases", "phrases", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var phrases []struct{ Gram1, Gram2 string }
+		r.json(t, &phrases)
+		if len(phrases) == 0 || (phrases[0].Gram1 != "cat" && phrases[0].Gram2 != "cat") {
+			t.Errorf("phrases = %+v", phrases)
+		}
+	}},
+	{"scrabble score", "scrabble_score", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		if r.text != "5" {
+			t.Errorf("score = %q, want 5", r.text)
+		}
+	}},
+	{"frequency", "word_frequency", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var f struct {
+			Word      string
+			Frequency []struct{ Count int }
+		}
+		r.json(t, &f)
+		if f.Word != "cat" || len(f.Frequency) == 0 {
+			t.Errorf("frequency = %+v", f)
+		}
+	}},
+	{"compare frequency", "compare_word_frequency", map[string]any{"words": []string{"cat", "qwxz", "serendipity"}, "startYear": 2000, "endYear": 2010}, func(t *testing.T, r result) {
+		var c struct {
+			Series []struct {
+				Word   string `json:"word"`
+				Counts []float64
+			} `json:"series"`
+			Missing []string `json:"missing"`
+		}
+		r.structured(t, &c)
+		if len(c.Series) != 2 || c.Series[0].Word != "cat" || c.Series[1].Word != "serendipity" {
+			t.Errorf("series = %+v", c.Series)
+		}
+		if !slices.Equal(c.Missing, []string{"qwxz"}) {
+			t.Errorf("missing = %q, want the word without data", c.Missing)
+		}
+		if !strings.Contains(r.text, "| cat |") {
+			t.Errorf("markdown lacks the table row for cat:\n%s", r.text)
+		}
+	}},
+	{"audio", "word_audio", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var audio []struct {
+			FileURL string `json:"fileUrl"`
+		}
+		r.json(t, &audio)
+		if len(audio) == 0 || audio[0].FileURL == "" {
+			t.Errorf("audio = %+v", audio)
+		}
+	}},
+	{"audio content", "word_audio", map[string]any{"word": "cat", "download": "content"}, func(t *testing.T, r result) {
+		if len(r.Content) != 2 {
+			t.Fatalf("got %d contents, want the metadata and one file", len(r.Content))
+		}
+		a, ok := r.Content[1].(mcp.AudioContent)
+		if !ok {
+			t.Fatalf("second content is %T, want audio", r.Content[1])
+		}
+		data, err := base64.StdEncoding.DecodeString(a.Data)
+		if err != nil || a.MIMEType != "audio/mpeg" || !bytes.HasPrefix(data, []byte("ID3")) {
+			t.Errorf("audio content %s of %q, %v", a.MIMEType, data, err)
+		}
+	}},
+	{"random words", "random_words", map[string]any{"limit": 3}, func(t *testing.T, r result) {
+		var words []struct{ Word string }
+		r.json(t, &words)
+		if len(words) != 3 {
+			t.Errorf("random words = %+v, want 3", words)
+		}
+	}},
+	{"random word", "random_word", nil, func(t *testing.T, r result) {
+		var w struct{ Word string }
+		r.json(t, &w)
+		if w.Word == "" {
+			t.Errorf("random word = %s", r.text)
+		}
+	}},
+	{"search", "search_words", map[string]any{"query": "^cat", "allowRegex": "true", "limit": 2}, func(t *testing.T, r result) {
+		var s struct {
+			TotalResults  int `json:"totalResults"`
+			SearchResults []struct{ Word string }
+		}
+		r.json(t, &s)
+		if len(s.SearchResults) != 2 || s.TotalResults < 2 || !strings.HasPrefix(s.SearchResults[0].Word, "cat") {
+			t.Errorf("search = %+v", s)
+		}
+	}},
+	{"examples", "examples", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var e struct{ Examples []struct{ Text string } }
+		r.json(t, &e)
+		if len(e.Examples) == 0 || !strings.Contains(e.Examples[0].Text, "cat") {
+			t.Errorf("examples = %+v", e)
+		}
+	}},
+	{"top example", "top_example", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var e struct{ Word, Text string }
+		r.json(t, &e)
+		if e.Word != "cat" || e.Text == "" {
+			t.Errorf("top example = %+v", e)
+		}
+	}},
+	{"hyphenation", "hyphenation", map[string]any{"word": "serendipity"}, func(t *testing.T, r result) {
+		var syllables []struct{ Text string }
+		r.json(t, &syllables)
+		var parts []string
+		for _, s := range syllables {
+			parts = append(parts, s.Text)
+		}
+		if got := strings.Join(parts, "-"); got != "ser-en-dip-i-ty" {
+			t.Errorf("syllables = %s", got)
+		}
+	}},
+	{"etymologies", "etymology", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var etymologies []string
+		r.json(t, &etymologies)
+		if len(etymologies) == 0 || !strings.Contains(etymologies[0], "cattus") {
+			t.Errorf("etymologies = %q", etymologies)
+		}
+	}},
+	{"related words", "related_words", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var related []struct {
+			RelationshipType string
+			Words            []string
+		}
+		r.json(t, &related)
+		if len(related) == 0 || related[0].RelationshipType == "" || len(related[0].Words) == 0 {
+			t.Errorf("related words = %+v", related)
+		}
+	}},
+	{"word graph", "word_graph", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var g graph
+		r.structured(t, &g)
+		words := make([]string, len(g.Nodes))
+		for i, n := range g.Nodes {
+			words[i] = n.Word
+		}
+		edges := make([]string, len(g.Edges))
+		for i, e := range g.Edges {
+			edges[i] = e.From + " " + e.Type + " " + e.To
+		}
+		if !slices.Equal(words, []string{"cat", "feline", "kitty", "puss", "tabby"}) || g.Truncated {
+			t.Errorf("nodes = %q, truncated %v", words, g.Truncated)
+		}
+		want := []string{"cat synonym feline", "cat synonym kitty", "cat synonym puss", "feline synonym kitty", "feline synonym tabby"}
+		if !slices.Equal(edges, want) {
+			t.Errorf("edges = %q, want %q", edges, want)
+		}
+	}},
+	{"word graph mermaid", "word_graph", map[string]any{"word": "cat", "relationshipTypes": []string{"synonym", "hypernym"}, "maxNodes": 4, "format": "mermaid"}, func(t *testing.T, r result) {
+		var g graph
+		r.structured(t, &g)
+		if len(g.Nodes) != 4 || !g.Truncated {
+			t.Errorf("%d nodes, truncated %v, want 4 and truncated", len(g.Nodes), g.Truncated)
+		}
+		if !strings.HasPrefix(r.text, "graph LR\n") || !strings.Contains(r.text, `n0 -- "synonym" --- n1`) {
+			t.Errorf("mermaid:\n%s", r.text)
+		}
+	}},
+	{"word graph dot", "word_graph", map[string]any{"word": "cat", "depth": 1, "format": "dot"}, func(t *testing.T, r result) {
+		if !strings.HasPrefix(r.text, "digraph word_graph {") || !strings.Contains(r.text, `n0 -> n1 [label="synonym", dir=none];`) || strings.Contains(r.text, "tabby") {
+			t.Errorf("dot:\n%s", r.text)
+		}
+	}},
+	{"pronunciations", "pronunciations", map[string]any{"word": "cat"}, func(t *testing.T, r result) {
+		var prons []struct{ Raw, RawType string }
+		r.json(t, &prons)
+		if !slices.ContainsFunc(prons, func(p struct{ Raw, RawType string }) bool { return p.RawType == "arpabet" }) {
+			t.Errorf("pronunciations = %+v, want an arpabet one", prons)
+		}
+	}},
+	{"word of the day", "word_of_the_day", map[string]any{"date": "2024-01-01"}, func(t *testing.T, r result) {
+		var w struct{ Word string }
+		r.json(t, &w)
+		if w.Word != "lexicon" {
+			t.Errorf("word of the day = %q, want lexicon", w.Word)
+		}
+	}},
+	{"word of the day archive", "word_of_the_day_archive", map[string]any{"startDate": "2024-01-01", "endDate": "2024-01-02"}, func(t *testing.T, r result) {
+		var days []struct{ Date, Word string }
+		r.json(t, &days)
+		if len(days) != 2 || days[0].Word != "lexicon" || days[1].Date != "2024-01-02" {
+			t.Errorf("archive = %+v", days)
+		}
+	}},
+	{"reverse dictionary", "reverse_dictionary", map[string]any{"query": "small pet"}, func(t *testing.T, r result) {
+		var rd struct{ Results []struct{ Word string } }
+		r.json(t, &rd)
+		if len(rd.Results) == 0 || rd.Results[0].Word != "cat" {
+			t.Errorf("reverse dictionary = %+v", rd)
+		}
+	}},
+	{"scan text", "scan_text", map[string]any{"text": "Shall I compare thee to a summer's day?"}, func(t *testing.T, r result) {
+		var s struct {
+			Lines []struct {
+				Syllables int    `json:"syllables"`
+				Meter     string `json:"meter"`
+			} `json:"lines"`
+		}
+		r.structured(t, &s)
+		if len(s.Lines) != 1 || s.Lines[0].Syllables != 10 || s.Lines[0].Meter != "iambic pentameter" {
+			t.Errorf("scan = %+v", s.Lines)
+		}
+	}},
+	{"resolve word", "resolve_word", map[string]any{"word": "cats"}, func(t *testing.T, r result) {
+		var res struct {
+			CanonicalForm string `json:"canonicalForm"`
+			HasEntries    bool   `json:"hasEntries"`
+		}
+		r.structured(t, &res)
+		if res.CanonicalForm != "cat" || !res.HasEntries {
+			t.Errorf("resolution = %+v", res)
+		}
+	}},
+	{"search museums", "search_museums", map[string]any{"language": "Basque"}, func(t *testing.T, r result) {
+		var s struct {
+			Total   int `json:"total"`
+			Results []struct {
+				Slug      string   `json:"slug"`
+				Languages []string `json:"languages"`
+			} `json:"results"`
+		}
+		r.structured(t, &s)
+		if s.Total == 0 || len(s.Results) != s.Total {
+			t.Fatalf("search = %+v", s)
+		}
+		for _, m := range s.Results {
+			if !slices.Contains(m.Languages, "eus") {
+				t.Errorf("%s is not about Basque: %v", m.Slug, m.Languages)
+			}
+		}
+	}},
+	{"get museum", "get_museum", map[string]any{"slug": "museum-of-the-basque-language"}, func(t *testing.T, r result) {
+		var m struct {
+			City  string            `json:"city"`
+			Names map[string]string `json:"names"`
+		}
+		r.structured(t, &m)
+		if m.City != "Bilbao" || m.Names["eu"] != "Euskararen Etxea" {
+			t.Errorf("museum = %+v", m)
+		}
+	}},
+	{"list museum countries", "list_museum_countries", nil, func(t *testing.T, r result) {
+		var c struct {
+			Countries []struct {
+				Country string `json:"country"`
+				Museums int    `json:"museums"`
+			} `json:"countries"`
+		}
+		r.structured(t, &c)
+		if len(c.Countries) < 10 || c.Countries[0].Museums == 0 {
+			t.Errorf("countries = %+v", c.Countries)
+		}
+	}},
+	{"museum languages", "get_museum_languages", map[string]any{"slug": "museum-of-the-basque-language"}, func(t *testing.T, r result) {
+		var l struct {
+			Languages []struct {
+				Code        string       `json:"code"`
+				Definitions []definition `json:"definitions"`
+			} `json:"languages"`
+		}
+		r.structured(t, &l)
+		if len(l.Languages) != 1 || l.Languages[0].Code != "eus" || len(l.Languages[0].Definitions) == 0 {
+			t.Errorf("languages = %+v", l.Languages)
+		}
+	}},
+}
+
+// graph is the structured content of word_graph.
+type graph struct {
+	Nodes []struct{ Word string }
+	Edges []struct {
+		From, To, Type string
+		Directed       bool
+	}
+	Truncated bool
+}
+
+// errorCall is a tools/call that must fail with code, optionally while the
+// mock injects fault.
+type errorCall struct {
+	name  string
+	tool  string
+	args  map[string]any
+	fault *wordnikmock.Fault
+	code  string
+}
+
+var errorCalls = []errorCall{
+	{"missing argument", "define", nil, nil, "bad_input"},
+	{"frequency years out of range", "compare_word_frequency", map[string]any{"words": []string{"cat"}, "startYear": -2e9, "endYear": 2e9}, nil, "bad_input"},
+	{"invalid date range", "word_of_the_day_archive", map[string]any{"startDate": "2024-01-02", "endDate": "2024-01-01"}, nil, "bad_input"},
+	{"invalid relationship type", "word_graph", map[string]any{"word": "cat", "relationshipTypes": "synonym,cousin"}, nil, "bad_input"},
+	{"unknown museum", "get_museum", map[string]any{"slug": "museum-of-the-basque"}, nil, "not_found"},
+	{"rate limited", "scrabble_score", map[string]any{"word": "serendipity"},
+		&wordnikmock.Fault{Kind: "429", Pattern: "/word.json/*/scrabbleScore"}, "rate_limited"},
+	{"upstream failure", "random_word", nil,
+		&wordnikmock.Fault{Kind: "500"}, "upstream_unavailable"},
+	{"malformed response", "etymology", map[string]any{"word": "serendipity"},
+		&wordnikmock.Fault{Kind: wordnikmock.Malformed}, "decode_error"},
+}
+
+func TestTransports(t *testing.T) {
+	for _, tc := range transports {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.Reset()
+			c := tc.connect(t)
+			t.Run("tools/list", func(t *testing.T) { checkToolList(t, c) })
+			t.Run("tools/call", func(t *testing.T) {
+				for _, call := range toolCalls {
+					t.Run(call.name, func(t *testing.T) {
+						r := callTool(t, c, call.tool, call.args)
+						if r.IsError {
+							t.Fatalf("error result: %s", r.text)
+						}
+						call.check(t, r)
+					})
+				}
+			})
+			t.Run("errors", func(t *testing.T) {
+				for _, call := range errorCalls {
+					t.Run(call.name, func(t *testing.T) {
+						if call.fault != nil {
+							mock.SetFaults(*call.fault)
+							defer mock.SetFaults()
+						}
+						checkError(t, callTool(t, c, call.tool, call.args), call.code)
+					})
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				req := mcp.CallToolRequest{}
+				req.Params.Name = "no_such_tool"
+				if _, err := c.CallTool(ctx, req); err == nil {
+					t.Errorf("calling an unknown tool succeeded")
+				}
+			})
+			t.Run("prompts", func(t *testing.T) { checkPrompts(t, c) })
+			t.Run("upstream requests", func(t *testing.T) { checkUpstream(t) })
+		})
+	}
+}
+
+// TestHTTPConfigPerRequest checks that each HTTP session uses the API
+// settings of its own headers.
+func TestHTTPConfigPerRequest(t *testing.T) {
+	srv := startHTTP(t, false)
+	good := srv.connect(t, map[string]string{"API_BASE_URL": mockURL, "API_KEY": apiKey})
+	bad := srv.connect(t, map[string]string{"API_BASE_URL": mockURL, "API_KEY": "wrong"})
+
+	checkError(t, callTool(t, bad, "scrabble_score", map[string]any{"word": "cat"}), "unauthorized")
+	if r := callTool(t, good, "scrabble_score", map[string]any{"word": "cat"}); r.IsError || r.text != "5" {
+		t.Errorf("the session with the right key got %s", r.text)
+	}
+}
+
+// TestAudioProxy checks that word_audio links files through /audio/{id} in
+// HTTP mode, which keep working once the upstream file is gone, and that
+// STDIO mode refuses to.
+func TestAudioProxy(t *testing.T) {
+	srv := startHTTP(t, false)
+	c := srv.connect(t, map[string]string{"API_BASE_URL": mockURL, "API_KEY": apiKey})
+	r := callTool(t, c, "word_audio", map[string]any{"word": "serendipity", "download": "proxy"})
+	var files []struct {
+		ProxyURL      string `json:"proxyUrl"`
+		DownloadError string `json:"downloadError"`
+	}
+	r.json(t, &files)
+	if len(files) != 1 || !strings.HasPrefix(files[0].ProxyURL, srv.base+"/audio/") {
+		t.Fatalf("files = %+v, want a proxy URL under %s", files, srv.base)
+	}
+
+	mock.SetFaults(wordnikmock.Fault{Kind: "404", Pattern: "/audio/*"})
+	defer mock.SetFaults()
+	resp, err := srv.client.Get(files[0].ProxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/mpeg" || !bytes.Contains(data, []byte("serendipity")) {
+		t.Errorf("GET proxy URL = %d %q %q", resp.StatusCode, resp.Header.Get("Content-Type"), data)
+	}
+
+	// A file that cannot be downloaded is reported, not fatal.
+	r = callTool(t, c, "word_audio", map[string]any{"word": "cat", "download": "proxy"})
+	files = nil
+	r.json(t, &files)
+	if r.IsError || len(files) != 1 || files[0].ProxyURL != "" || !strings.HasPrefix(files[0].DownloadError, "not_found") {
+		t.Errorf("failed download = %+v", files)
+	}
+
+	checkError(t, callTool(t, connectStdio(t), "word_audio", map[string]any{"word": "cat", "download": "proxy"}), "bad_input")
+
+	// The API a caller names in its headers does not make its host trusted.
+	strict := startHTTP(t, false, "AUDIO_HOSTS=wordnik.com").connect(t, map[string]string{"API_BASE_URL": mockURL, "API_KEY": apiKey})
+	r = callTool(t, strict, "word_audio", map[string]any{"word": "cat", "download": "content"})
+	files = nil
+	r.json(t, &files)
+	if len(r.Content) != 1 || len(files) != 1 || !strings.Contains(files[0].DownloadError, "not allowed") {
+		t.Errorf("download from a host not allowed = %+v with %d contents", files, len(r.Content))
+	}
+}
+
+// TestDemoMode checks that DEMO=true serves the built-in cassette with no
+// API settings and without reaching any API.
+func TestDemoMode(t *testing.T) {
+	mock.Reset()
+	sessions := map[string]*mcpclient.Client{
+		"stdio": startStdio(t, "DEMO=true", "API_BASE_URL=", "API_KEY="),
+		"http":  startHTTP(t, false, "DEMO=true").connect(t, nil),
+	}
+	for name, c := range sessions {
+		t.Run(name, func(t *testing.T) {
+			r := callTool(t, c, "define", map[string]any{"word": "cat"})
+			if r.IsError {
+				t.Fatalf("error result: %s", r.text)
+			}
+			var defs []definition
+			r.json(t, &defs)
+			if len(defs) == 0 || !strings.Contains(defs[0].Text, "mammal") {
+				t.Errorf("definitions of cat: %s", r.text)
+			}
+			// A request the cassette lacks fails rather than reaching the API.
+			checkError(t, callTool(t, c, "define", map[string]any{"word": "zebra"}), "upstream_unavailable")
+		})
+	}
+	if reqs := mock.Requests(); len(reqs) != 0 {
+		t.Errorf("demo mode sent %d requests to the mock", len(reqs))
+	}
+}
+
+// TestToolSelection checks that TOOLS_PROFILE, TOOLS_FILE and TOOLS limit
+// the tools and resources listed and callable.
+func TestToolSelection(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tools.txt")
+	if err := os.WriteFile(file, []byte("# no audio\n-word_audio\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		env       []string
+		tools     []string // a subset of the tools listed
+		hidden    []string
+		resources bool
+		prompts   []string
+	}{
+		{"lexical without audio", []string{"TOOLS_PROFILE=lexical", "TOOLS_FILE=" + file},
+			[]string{"define", "random_word"},
+			[]string{"word_audio", "search_museums"}, false,
+			[]string{"compare_synonyms", "explain_word", "vocabulary_quiz"}},
+		{"museums", []string{"TOOLS=museum.*,-museum://*"},
+			[]string{"search_museums", "get_museum"},
+			[]string{"define"}, true,
+			[]string{"describe_museum"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startStdio(t, append(tt.env, "API_BASE_URL="+mockURL, "API_KEY="+apiKey)...)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			list, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var listed []string
+			for _, tool := range list.Tools {
+				listed = append(listed, tool.Name)
+			}
+			for _, name := range tt.tools {
+				if !slices.Contains(listed, name) {
+					t.Errorf("%s not listed in %v", name, listed)
+				}
+			}
+			for _, name := range tt.hidden {
+				if slices.Contains(listed, name) {
+					t.Errorf("%s listed", name)
+				}
+				req := mcp.CallToolRequest{}
+				req.Params.Name = name
+				req.Params.Arguments = map[string]any{"word": "cat"}
+				if _, err := c.CallTool(ctx, req); err == nil {
+					t.Errorf("calling the hidden tool %s succeeded", name)
+				}
+			}
+
+			resources, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var uris []string
+			for _, r := range resources.Resources {
+				uris = append(uris, r.URI)
+			}
+			if got := slices.Contains(uris, "museums://index"); got != tt.resources {
+				t.Errorf("museums://index listed: %v, want %v", got, tt.resources)
+			}
+			for _, uri := range uris {
+				if strings.HasPrefix(uri, "museum://") {
+					t.Errorf("excluded resource %s listed", uri)
+				}
+			}
+
+			prompts, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, p := range prompts.Prompts {
+				names = append(names, p.Name)
+			}
+			if !slices.Equal(names, tt.prompts) {
+				t.Errorf("prompts = %v, want %v", names, tt.prompts)
+			}
+		})
+	}
+}
+
+// TestDeprecatedNames checks that the generated tool names are still
+// callable, but not listed.
+func TestDeprecatedNames(t *testing.T) {
+	c := connectStdio(t)
+	r := callTool(t, c, "get_word_json_word_scrabbleScore", map[string]any{"word": "cat"})
+	if r.IsError || r.text != "5" {
+		t.Errorf("get_word_json_word_scrabbleScore = %+v, want 5", r)
+	}
+	checkError(t, callTool(t, c, "get_word_json_word_definitions", nil), "bad_input")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	list, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range list.Tools {
+		if strings.HasPrefix(tool.Name, "get_word") {
+			t.Errorf("deprecated name %s listed", tool.Name)
+		}
+	}
+}
+
+func prompt(name string) mcp.PromptReference {
+	return mcp.PromptReference{Type: "ref/prompt", Name: name}
+}
+
+// checkPrompts lists and gets the prompts, completes the arguments of the
+// prompts and resource templates and reads a country's museums.
+func checkPrompts(t *testing.T, c *mcpclient.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range list.Prompts {
+		names = append(names, p.Name)
+	}
+	if want := []string{"compare_synonyms", "describe_museum", "explain_word", "vocabulary_quiz"}; !slices.Equal(names, want) {
+		t.Errorf("prompts = %v, want %v", names, want)
+	}
+
+	get := func(name string, args map[string]string) (*mcp.GetPromptResult, error) {
+		req := mcp.GetPromptRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		return c.GetPrompt(ctx, req)
+	}
+	res, err := get("explain_word", map[string]string{"word": "cat", "audience": "child", "partOfSpeech": "noun, verb", "sourceDictionary": "wordnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := res.Messages[0].Content.(mcp.TextContent); !ok || !strings.Contains(text.Text, `"cat" for child`) ||
+		!strings.Contains(text.Text, "partOfSpeech=noun,verb") || !strings.Contains(text.Text, "sourceDictionaries=wordnet") {
+		t.Errorf("explain_word = %+v", res.Messages)
+	}
+	if _, err := get("compare_synonyms", map[string]string{"words": "cat", "relationshipTypes": "synonym,friend"}); err == nil || !strings.Contains(err.Error(), `"friend"`) {
+		t.Errorf("compare_synonyms with an invalid relationship type: %v", err)
+	}
+	res, err = get("describe_museum", map[string]string{"slug": "alutiiq-museum"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := res.Messages[0].Content.(mcp.EmbeddedResource); !ok {
+		t.Errorf("describe_museum starts with %T, want the museum record", res.Messages[0].Content)
+	} else if contents, ok := r.Resource.(mcp.TextResourceContents); !ok || contents.URI != "museum://alutiiq-museum" {
+		t.Errorf("describe_museum embeds %+v", r.Resource)
+	}
+	if _, err := get("vocabulary_quiz", map[string]string{"words": "cat,dog", "style": "essay"}); err == nil || !strings.Contains(err.Error(), "invalid style") {
+		t.Errorf("vocabulary_quiz with an invalid style: %v", err)
+	}
+	if _, err := get("describe_museum", map[string]string{"slug": "alutiiq"}); err == nil || !strings.Contains(err.Error(), "alutiiq-museum") {
+		t.Errorf("describe_museum with an unknown slug: %v", err)
+	}
+
+	tests := []struct {
+		ref        any
+		arg, value string
+		want       []string
+	}{
+		{prompt("explain_word"), "word", "ser", []string{"serendipity", "serene", "serenity"}},
+		{prompt("explain_word"), "word", "ser", []string{"serendipity", "serene", "serenity"}},
+		{prompt("explain_word"), "word", "", []string{}},
+		{prompt("explain_word"), "partOfSpeech", "noun,ve", []string{"noun,verb", "noun,verb-intransitive", "noun,verb-transitive"}},
+		{prompt("explain_word"), "sourceDictionary", "w", []string{"wiktionary", "webster", "wordnet"}},
+		{prompt("compare_synonyms"), "words", "cat, kit", []string{"cat, kitten"}},
+		{prompt("compare_synonyms"), "relationshipTypes", "synonym,ant", []string{"synonym,antonym"}},
+		{prompt("vocabulary_quiz"), "style", "f", []string{"fill-in-the-blank"}},
+		{prompt("describe_museum"), "slug", "alut", []string{"alutiiq-museum"}},
+		{mcp.ResourceReference{Type: "ref/resource", URI: "museum://{slug}"}, "slug", "alut", []string{"alutiiq-museum"}},
+		{mcp.ResourceReference{Type: "ref/resource", URI: "museums://country/{country}"}, "country", "sw", []string{"Switzerland"}},
+	}
+	searches := func() int {
+		n := 0
+		for _, r := range mock.Requests() {
+			if strings.HasPrefix(r.Path, "/words.json/search/") {
+				n++
+			}
+		}
+		return n
+	}
+	before := searches()
+	for _, tt := range tests {
+		req := mcp.CompleteRequest{}
+		req.Params.Ref = tt.ref
+		req.Params.Argument.Name = tt.arg
+		req.Params.Argument.Value = tt.value
+		res, err := c.Complete(ctx, req)
+		if err != nil {
+			t.Errorf("complete %v %s %q: %v", tt.ref, tt.arg, tt.value, err)
+			continue
+		}
+		if got := res.Completion.Values; !slices.Equal(got, tt.want) {
+			t.Errorf("complete %v %s %q = %q, want %q", tt.ref, tt.arg, tt.value, got, tt.want)
+		}
+	}
+	// "ser" and "kit" are searched once each; the second "ser" is cached,
+	// unless an earlier session already searched it.
+	if n := searches() - before; n > 2 {
+		t.Errorf("completions sent %d word searches, want at most 2", n)
+	}
+
+	read := mcp.ReadResourceRequest{}
+	read.Params.URI = "museums://country/Spain"
+	country, err := c.ReadResource(ctx, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := country.Contents[0].(mcp.TextResourceContents); !ok || !strings.Contains(text.Text, `"uri": "museum://`) {
+		t.Errorf("museums://country/Spain = %+v", country.Contents)
+	}
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.PromptReference{Type: "ref/prompt", Name: "no_such_prompt"}
+	if _, err := c.Complete(ctx, req); err == nil {
+		t.Errorf("completing an unknown prompt succeeded")
+	}
+}
+
+func checkToolList(t *testing.T, c *mcpclient.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	list, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := map[string]bool{}
+	for _, tool := range list.Tools {
+		listed[tool.Name] = true
+		if tool.Description == "" {
+			t.Errorf("%s has no description", tool.Name)
+		}
+		if a := tool.Annotations; a.Title == "" || a.ReadOnlyHint == nil || !*a.ReadOnlyHint || a.OpenWorldHint == nil {
+			t.Errorf("%s annotations = %+v, want a title and hints", tool.Name, a)
+		}
+		if tool.InputSchema.Type != "object" {
+			t.Errorf("%s input schema has type %q", tool.Name, tool.InputSchema.Type)
+		}
+		for _, name := range tool.InputSchema.Required {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				t.Errorf("%s requires undeclared argument %q", tool.Name, name)
+			}
+		}
+	}
+	called := map[string]bool{}
+	for _, call := range toolCalls {
+		called[call.tool] = true
+		if !listed[call.tool] {
+			t.Errorf("%s is not listed", call.tool)
+		}
+	}
+	for name := range listed {
+		if !called[name] {
+			t.Errorf("%s has no end-to-end test; add it to toolCalls", name)
+		}
+	}
+}
+
+// checkUpstream checks what reached the mock during the calls: the API
+// key on every request and words escaped as one path segment.
+func checkUpstream(t *testing.T) {
+	reqs := mock.Requests()
+	if len(reqs) == 0 {
+		t.Fatal("no requests reached the mock")
+	}
+	escaped := false
+	for _, r := range reqs {
+		if strings.HasPrefix(r.Path, "/audio/") {
+			// Audio files are fetched from their URLs, without the key.
+			if r.Query.Has("api_key") {
+				t.Errorf("%s sent the api_key", r.Path)
+			}
+			continue
+		}
+		if r.Query.Get("api_key") != apiKey {
+			t.Errorf("%s sent api_key %q", r.Path, r.Query.Get("api_key"))
+		}
+		if r.Path == "/word.json/AC%2FDC/definitions" {
+			escaped = true
+		}
+	}
+	if !escaped {
+		t.Errorf("no request for /word.json/AC%%2FDC/definitions among %d", len(reqs))
+	}
+}
+
+func callTool(t *testing.T, c *mcpclient.Client, tool string, args map[string]any) result {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = tool
+	req.Params.Arguments = args
+	res, err := c.CallTool(ctx, req)
+	if err != nil {
+		t.Fatalf("tools/call %s: %v", tool, err)
+	}
+	r := result{CallToolResult: res}
+	if len(res.Content) > 0 {
+		if text, ok := res.Content[0].(mcp.TextContent); ok {
+			r.text = text.Text
+		}
+	}
+	return r
+}
+
+func checkError(t *testing.T, r result, code string) {
+	t.Helper()
+	if !r.IsError {
+		t.Fatalf("want a %s error, got %s", code, r.text)
+	}
+	var p errorPayload
+	r.structured(t, &p)
+	if p.Code != code || !strings.HasPrefix(r.text, code+": ") {
+		t.Errorf("error %q (code %q), want code %s", r.text, p.Code, code)
+	}
+}
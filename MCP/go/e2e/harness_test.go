@@ -0,0 +1,375 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+// apiKey is the key the mock requires, so that every test also checks that
+// the key reaches the API.
+const apiKey = "e2e-key"
+
+var (
+	// binDir holds the server binary, built by the first test that needs
+	// it.
+	binDir    string
+	buildOnce sync.Once
+	serverBin string
+	buildErr  error
+	// mock is the Wordnik stand-in shared by every test.
+	mock    *wordnikmock.Server
+	mockURL string
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		fmt.Println("skipping end-to-end tests in short mode")
+		os.Exit(0)
+	}
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "wordnik-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	binDir = dir
+
+	mock, err = wordnikmock.New(wordnikmock.Options{APIKey: apiKey})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+	mockURL = ts.URL + "/v4"
+
+	return m.Run()
+}
+
+// buildServer builds the server binary once per run and returns its path.
+func buildServer(t *testing.T) string {
+	t.Helper()
+	buildOnce.Do(func() {
+		// Read the sources from a test rather than TestMain: go test only
+		// tracks the files opened once m.Run has started.
+		if buildErr = readModule(".."); buildErr != nil {
+			return
+		}
+		serverBin = filepath.Join(binDir, "wordnik-mcp")
+		out, err := exec.Command("go", "build", "-o", serverBin, "..").CombinedOutput()
+		if err != nil {
+			buildErr = fmt.Errorf("building the server: %v\n%s", err, out)
+		}
+	})
+	if buildErr != nil {
+		t.Fatal(buildErr)
+	}
+	return serverBin
+}
+
+// readModule reads every file of the module. go test caches results by
+// the files a test opens, and the build of the server binary is invisible
+// to it; reading the sources makes a change anywhere in the server run the
+// tests again.
+func readModule(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		_, err = os.ReadFile(path)
+		return err
+	})
+}
+
+// baseEnv is the environment every server process starts with. Settings
+// from the developer's shell that change behavior are cleared.
+func baseEnv() []string {
+	return []string{
+		"RATE_LIMIT=0",
+		"MUSEUMS_DIR=",
+		"RETRY_WITH_SUGGESTION=",
+		"BEARER_TOKEN=",
+		"BASIC_AUTH=",
+		"DEMO=",
+		"CASSETTE_FILE=",
+		"CASSETTE_MODE=",
+		"TOOLS=",
+		"TOOLS_PROFILE=",
+		"TOOLS_FILE=",
+		// The mock serves the audio files as well as the API, but HTTP
+		// mode only trusts the hosts configured.
+		"AUDIO_HOSTS=127.0.0.1",
+	}
+}
+
+// A transportCase starts the server in one transport mode and returns an
+// initialized client for it.
+type transportCase struct {
+	name    string
+	connect func(t *testing.T) *mcpclient.Client
+}
+
+var transports = []transportCase{
+	{"stdio", connectStdio},
+	{"http", func(t *testing.T) *mcpclient.Client { return connectHTTP(t, false) }},
+	{"https", func(t *testing.T) *mcpclient.Client { return connectHTTP(t, true) }},
+}
+
+func connectStdio(t *testing.T) *mcpclient.Client {
+	t.Helper()
+	return startStdio(t, "API_BASE_URL="+mockURL, "API_KEY="+apiKey)
+}
+
+// startStdio starts the server in STDIO mode with env added to baseEnv and
+// returns an initialized client for it.
+func startStdio(t *testing.T, env ...string) *mcpclient.Client {
+	t.Helper()
+	env = append(append(baseEnv(), "TRANSPORT=stdio"), env...)
+	c, err := mcpclient.NewStdioMCPClient(buildServer(t), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := &syncBuffer{}
+	if stderr, ok := mcpclient.GetStderr(c); ok {
+		go io.Copy(logs, stderr)
+	}
+	t.Cleanup(func() {
+		// Closing stdin ends the session; the server must then exit. The
+		// client closes stderr first, so the server's last log line may end
+		// it with SIGPIPE: only its exit is checked, not its status.
+		done := make(chan error, 1)
+		go func() { done <- c.Close() }()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Errorf("server did not exit after stdin was closed")
+		}
+		if t.Failed() {
+			t.Logf("server log:\n%s", logs)
+		}
+	})
+	initialize(t, c)
+	return c
+}
+
+// httpServer is the server running in HTTP or HTTPS mode.
+type httpServer struct {
+	base   string
+	client *http.Client
+}
+
+// connectHTTP starts the server in HTTP or HTTPS mode and connects to it
+// with the API settings of the mock.
+func connectHTTP(t *testing.T, useTLS bool) *mcpclient.Client {
+	t.Helper()
+	return startHTTP(t, useTLS).connect(t, map[string]string{"API_BASE_URL": mockURL, "API_KEY": apiKey})
+}
+
+// startHTTP starts the server in HTTP or HTTPS mode on a free port, with
+// extra added to its environment, and waits until it is healthy. It is
+// stopped with SIGINT when t ends.
+func startHTTP(t *testing.T, useTLS bool, extra ...string) *httpServer {
+	t.Helper()
+	port := freePort(t)
+	env := append(baseEnv(), "PORT="+port, "API_BASE_URL=", "API_KEY=")
+	env = append(env, extra...)
+	srv := &httpServer{base: "http://127.0.0.1:" + port, client: &http.Client{}}
+	if useTLS {
+		certFile, keyFile, pool := selfSignedCert(t)
+		env = append(env, "TRANSPORT=https", "CERT_FILE="+certFile, "KEY_FILE="+keyFile)
+		srv.base = "https://127.0.0.1:" + port
+		srv.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	} else {
+		env = append(env, "TRANSPORT=http")
+	}
+
+	cmd := exec.Command(buildServer(t))
+	cmd.Env = append(os.Environ(), env...)
+	logs := &syncBuffer{}
+	cmd.Stdout, cmd.Stderr = logs, logs
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	t.Cleanup(func() {
+		cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+			t.Errorf("server did not shut down on SIGINT")
+		}
+		if t.Failed() {
+			t.Logf("server log:\n%s", logs)
+		}
+	})
+	waitHealthy(t, srv.client, srv.base, exited)
+	return srv
+}
+
+// connect opens an initialized MCP session on the /mcp endpoint, sending
+// headers, which carry the API settings in HTTP mode, with every request.
+func (s *httpServer) connect(t *testing.T, headers map[string]string) *mcpclient.Client {
+	t.Helper()
+	c, err := mcpclient.NewStreamableHttpClient(s.base+"/mcp",
+		transport.WithHTTPBasicClient(s.client),
+		transport.WithHTTPHeaders(headers),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, c)
+	return c
+}
+
+// waitHealthy polls the health check until the server answers.
+func waitHealthy(t *testing.T, httpClient *http.Client, base string, exited <-chan error) {
+	t.Helper()
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			t.Fatalf("server exited during startup: %v", err)
+		default:
+		}
+		resp, err := httpClient.Get(base + "/")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("server at %s did not become healthy", base)
+}
+
+func initialize(t *testing.T, c *mcpclient.Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "wordnik-e2e", Version: "1.0"}
+	res, err := c.Initialize(ctx, req)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if res.ServerInfo.Name != "Wordnik" {
+		t.Errorf("server name %q, want Wordnik", res.ServerInfo.Name)
+	}
+	if res.Capabilities.Tools == nil || res.Capabilities.Resources == nil {
+		t.Errorf("capabilities %+v lack tools or resources", res.Capabilities)
+	}
+}
+
+func freePort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+// selfSignedCert writes a certificate for 127.0.0.1 and localhost and its
+// key to a temporary directory, and returns a pool that trusts it.
+func selfSignedCert(t *testing.T) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "wordnik-e2e"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
+// syncBuffer collects a server's log output.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.TrimRight(b.buf.String(), "\n")
+}
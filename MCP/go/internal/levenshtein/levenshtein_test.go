@@ -0,0 +1,24 @@
+package levenshtein
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"cat", "", 3},
+		{"", "cat", 3},
+		{"cat", "cat", 0},
+		{"catt", "cat", 1},
+		{"cat", "cart", 1},
+		{"kitten", "sitting", 3},
+		{"müseum", "museum", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,16 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/wordnik/mcp-server/api"
+	"github.com/wordnik/mcp-server/audio"
+	"github.com/wordnik/mcp-server/cassette"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/completion"
 	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/museums/watch"
+	"github.com/wordnik/mcp-server/prompts"
+	"github.com/wordnik/mcp-server/registry"
+	"github.com/wordnik/mcp-server/resources"
 )
 
 func main() {
@@ -19,6 +32,31 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	client.SetRateLimit(cfg.RateLimit, int(math.Ceil(cfg.RateLimit)))
+	audio.SetLimits(cfg.AudioMaxBytes, cfg.AudioTTL)
+	audio.SetHosts(cfg.AudioHosts...)
+	rt, err := cassette.FromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load cassette: %v", err)
+	}
+	if rt != nil {
+		client.SetTransport(rt)
+	}
+	sel, err := registry.NewSelection(cfg.Tools)
+	if err != nil {
+		log.Fatalf("Invalid tool selection: %v", err)
+	}
+
+	if len(os.Args) > 1 {
+		run, ok := commands[os.Args[1]]
+		if !ok {
+			log.Fatalf("Unknown command %q", os.Args[1])
+		}
+		if err := run(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
 
 	// Check transport environment variable (both uppercase and lowercase)
 	transport := os.Getenv("TRANSPORT")
@@ -45,34 +83,41 @@ func main() {
 		
 		log.Printf("Running in %s mode on port %s", transport, port)
 
-		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
+		// One MCP server serves every session, so that sessions survive across
+		// requests and receive list-changed notifications. The API config is
+		// read from each request's headers and passed down in its context.
+		mcpSrv, completer := createMCPServer(cfg, sel, transport)
+		watchMuseums(cfg, sel, mcpSrv)
+		contextFunc := func(ctx context.Context, r *http.Request) context.Context {
+			reqCfg := headerConfig(r)
+			if reqCfg.BaseURL == "" {
+				// Only in demo mode; otherwise the request was refused.
+				reqCfg.BaseURL = cfg.BaseURL
 			}
+			return audio.WithProxyURL(config.WithContext(ctx, reqCfg), publicURL(r, cfg))
+		}
+		handler := completer.Handler(server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(contextFunc)), contextFunc)
 
-			if apiCfg.BaseURL == "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+			baseURL := r.Header.Get("API_BASE_URL")
+			if baseURL == "" && cfg.Demo {
+				baseURL = cfg.BaseURL
+			} else if baseURL == "" {
 				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
 				return
 			}
 
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
-
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
-
+			log.Printf("Incoming HTTP request - BaseURL: %s", baseURL)
 			handler.ServeHTTP(w, r)
 		})
 
+		// Audio files downloaded by word_audio with download=proxy.
+		mux.Handle("/audio/", audio.Handler())
+
+		// The museums dataset is also served as plain JSON for web apps.
+		mux.Handle("/api/", api.NewHandler(museums.DefaultStore(), api.ParseCORS(cfg.CORSAllowedOrigins)))
+
 		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.Write([]byte(`{"status":"ok"}`))
@@ -118,28 +163,120 @@ func main() {
 
 	// STDIO Mode - default when no transport or transport is "stdio"
 	log.Println("Running in STDIO mode")
-	mcp := createMCPServer(cfg, "STDIO")
+	mcp, completer := createMCPServer(cfg, sel, "STDIO")
+	watchMuseums(cfg, sel, mcp)
+	// The client ends the session by closing stdin, which makes ServeStdio
+	// return; exit then rather than wait for a signal that never comes.
+	done := make(chan error, 1)
 	go func() {
-		if err := server.ServeStdio(mcp); err != nil {
+		done <- completer.ServeStdio(context.Background(), mcp, os.Stdin, os.Stdout)
+	}()
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, context.Canceled) {
 			log.Fatalf("STDIO error: %v", err)
 		}
-	}()
-	<-sigChan
-	log.Println("Received shutdown signal. Exiting STDIO mode.")
+		log.Println("Input closed. Exiting STDIO mode.")
+	case <-sigChan:
+		log.Println("Received shutdown signal. Exiting STDIO mode.")
+	}
+}
+
+// publicURL returns the base URL clients reach this server at: the
+// AUDIO_PUBLIC_URL setting, or else the scheme and host of the request.
+func publicURL(r *http.Request, cfg *config.APIConfig) string {
+	if cfg.AudioPublicURL != "" {
+		return cfg.AudioPublicURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// headerConfig reads the API config of an HTTP/HTTPS request from its headers.
+func headerConfig(r *http.Request) *config.APIConfig {
+	return &config.APIConfig{
+		BaseURL:     r.Header.Get("API_BASE_URL"),
+		BearerToken: r.Header.Get("BEARER_TOKEN"),
+		APIKey:      r.Header.Get("API_KEY"),
+		BasicAuth:   r.Header.Get("BASIC_AUTH"),
+
+		RetryWithSuggestion: config.ParseBool(r.Header.Get("RETRY_WITH_SUGGESTION")),
+	}
 }
 
-func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
+// watchMuseums serves cfg.MuseumsDir instead of the embedded dataset, if
+// set, and reloads it in the background whenever its files change. Data
+// that fails validation is not served; the previous index stays in use.
+// After each reload the museum resources of s are registered again, which
+// notifies connected sessions with resources/list_changed.
+func watchMuseums(cfg *config.APIConfig, sel *registry.Selection, s *server.MCPServer) {
+	if cfg.MuseumsDir == "" {
+		return
+	}
+	store := museums.DefaultStore()
+	w := &watch.Watcher{
+		Dir:      cfg.MuseumsDir,
+		Store:    store,
+		Interval: cfg.MuseumsWatchInterval,
+		OnSwap: func(ix *museums.Index) {
+			log.Printf("Loaded %d museums from %s", ix.Len(), cfg.MuseumsDir)
+			resources.RegisterMuseums(s, store, sel.AllowsResource)
+		},
+		OnError: func(err error) {
+			log.Printf("Museums not reloaded, keeping the previous data: %v", err)
+		},
+	}
+	if _, err := w.Reload(); err != nil {
+		log.Printf("Cannot load museums from %s, serving the embedded dataset: %v", cfg.MuseumsDir, err)
+	}
+	if cfg.MuseumsWatchInterval == 0 {
+		return
+	}
+	log.Printf("Watching %s for museum changes every %s", cfg.MuseumsDir, cfg.MuseumsWatchInterval)
+	go w.Run(context.Background())
+}
+
+// createMCPServer returns a server with the tools and resources that sel
+// allows, and the prompts that use them. The deprecated tool names stay
+// callable but are not listed. The Completer answers completion requests
+// for the arguments of the prompts and resource templates.
+func createMCPServer(cfg *config.APIConfig, sel *registry.Selection, mode string) (*server.MCPServer, *completion.Completer) {
 	mcp := server.NewMCPServer("Wordnik", "4.0",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+		server.WithPromptCapabilities(false),
 		server.WithRecovery(),
+		server.WithToolFilter(registry.HideAliases),
 	)
 
-	tools := GetAll(cfg)
+	tools := registry.Selected(cfg, sel)
+	if sel != nil {
+		log.Printf("Tool selection %q", strings.Join(cfg.Tools, ","))
+	}
 	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
 
-	for _, tool := range tools {
+	for _, tool := range append(tools, registry.AliasTools(tools)...) {
 		mcp.AddTool(tool.Definition, tool.Handler)
 	}
 
-	return mcp
+	store := museums.DefaultStore()
+	resources.RegisterMuseums(mcp, store, sel.AllowsResource)
+
+	served := map[string]bool{}
+	for _, tool := range tools {
+		served[tool.Definition.Name] = true
+	}
+	completer := completion.New()
+	prompts.Register(mcp, completer, cfg, store, func(tool string) bool { return served[tool] })
+	if sel.AllowsResource(resources.MuseumTemplate) {
+		completer.Resource(resources.MuseumTemplate, "slug", completion.MuseumSlugs(store, sel.AllowsResource))
+	}
+	if sel.AllowsResource(resources.CountryTemplate) {
+		completer.Resource(resources.CountryTemplate, "country", completion.MuseumCountries(store))
+	}
+
+	return mcp, completer
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package models
+
+// Values of the Wordnik API enums, as listed in openapi.yml. Tools validate
+// arguments against them and completions offer them.
+var (
+	// SourceDictionaries are the dictionaries definitions can come from.
+	SourceDictionaries = []string{"ahd-5", "century", "wiktionary", "webster", "wordnet"}
+	// RelationshipTypes are the types of related words.
+	RelationshipTypes = []string{
+		"synonym", "antonym", "variant", "equivalent", "cross-reference", "related-word", "rhyme", "form",
+		"etymologically-related-term", "hypernym", "hyponym", "inflected-form", "primary", "same-context",
+		"verb-form", "verb-stem", "has_topic",
+	}
+	// PartsOfSpeech are the parts of speech searches and definitions filter
+	// on.
+	PartsOfSpeech = []string{
+		"noun", "adjective", "verb", "adverb", "interjection", "pronoun", "preposition", "abbreviation",
+		"affix", "article", "auxiliary-verb", "conjunction", "definite-article", "family-name", "given-name",
+		"idiom", "imperative", "noun-plural", "noun-posessive", "past-participle", "phrasal-prefix",
+		"proper-noun", "proper-noun-plural", "proper-noun-posessive", "suffix", "verb-intransitive",
+		"verb-transitive",
+	}
+)
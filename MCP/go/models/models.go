@@ -324,7 +324,7 @@ type Related struct {
 type User struct {
 	Password string `json:"password,omitempty"`
 	Status int `json:"status,omitempty"`
-	Username string `json:"userName,omitempty"`
+	UsernameField string `json:"userName,omitempty"`
 	Username string `json:"username,omitempty"`
 	Displayname string `json:"displayName,omitempty"`
 	Email string `json:"email,omitempty"`
@@ -0,0 +1,217 @@
+// Package geo resolves the free-text locations of the museums dataset, such
+// as "Kodiak, Alaska, USA", into a city, region, ISO 3166-1 country code and
+// coordinates, using a gazetteer compiled into the package. It makes no
+// network requests.
+//
+// The country table covers every ISO 3166-1 alpha-2 code. The city table
+// covers the places named in the dataset; add a line to cities.csv when a
+// museum in a new place is added. Coordinates are those of the town, not of
+// the museum, rounded to two decimals (about a kilometre).
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wordnik/mcp-server/museums"
+)
+
+//go:embed countries.csv
+var countriesCSV string
+
+//go:embed cities.csv
+var citiesCSV string
+
+// Country is an ISO 3166-1 country.
+type Country struct {
+	Code string // alpha-2, e.g. "US"
+	Name string // English short name, e.g. "United States"
+}
+
+// City is a place in the gazetteer.
+type City struct {
+	Name    string
+	Region  string
+	Country string // alpha-2 code
+	Lat     float64
+	Lon     float64
+}
+
+// Gazetteer looks up countries and cities by name, ignoring case and
+// accents.
+type Gazetteer struct {
+	countries map[string]Country // by code
+	byName    map[string]Country // by folded name, alias or code
+	cities    map[string]City    // by country code and folded name or alias
+}
+
+var (
+	defaultGazetteer     *Gazetteer
+	defaultGazetteerOnce sync.Once
+)
+
+// Default returns the gazetteer compiled into the package. It panics if the
+// embedded tables are malformed.
+func Default() *Gazetteer {
+	defaultGazetteerOnce.Do(func() {
+		g, err := New(countriesCSV, citiesCSV)
+		if err != nil {
+			panic("geo: " + err.Error())
+		}
+		defaultGazetteer = g
+	})
+	return defaultGazetteer
+}
+
+// New builds a gazetteer from CSV tables in the format of countries.csv
+// (code,name,aliases) and cities.csv (name,aliases,region,country,lat,lon).
+// Aliases are separated by "|".
+func New(countries, cities string) (*Gazetteer, error) {
+	g := &Gazetteer{
+		countries: map[string]Country{},
+		byName:    map[string]Country{},
+		cities:    map[string]City{},
+	}
+	rows, err := readCSV("countries.csv", countries, 3)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		c := Country{Code: r[0], Name: r[1]}
+		g.countries[c.Code] = c
+		for _, name := range append([]string{c.Code, c.Name}, aliases(r[2])...) {
+			g.byName[museums.Fold(name)] = c
+		}
+	}
+
+	rows, err = readCSV("cities.csv", cities, 6)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range rows {
+		c := City{Name: r[0], Region: r[2], Country: r[3]}
+		if _, ok := g.countries[c.Country]; !ok {
+			return nil, fmt.Errorf("cities.csv:%d: unknown country %q", i+2, c.Country)
+		}
+		if c.Lat, err = strconv.ParseFloat(r[4], 64); err != nil {
+			return nil, fmt.Errorf("cities.csv:%d: lat: %w", i+2, err)
+		}
+		if c.Lon, err = strconv.ParseFloat(r[5], 64); err != nil {
+			return nil, fmt.Errorf("cities.csv:%d: lon: %w", i+2, err)
+		}
+		for _, name := range append([]string{c.Name}, aliases(r[1])...) {
+			g.cities[cityKey(name, c.Country)] = c
+		}
+	}
+	return g, nil
+}
+
+func readCSV(name, data string, fields int) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = fields
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: missing header", name)
+	}
+	return rows[1:], nil
+}
+
+func aliases(field string) []string {
+	if field == "" {
+		return nil
+	}
+	return strings.Split(field, "|")
+}
+
+func cityKey(name, country string) string {
+	return country + "/" + museums.Fold(strings.TrimSpace(name))
+}
+
+// Country returns the country with the given alpha-2 code, name or alias,
+// such as "DE", "Germany" or "Deutschland".
+func (g *Gazetteer) Country(nameOrCode string) (Country, bool) {
+	c, ok := g.byName[museums.Fold(strings.TrimSpace(nameOrCode))]
+	return c, ok
+}
+
+// CountryByCode returns the country with the given alpha-2 code only.
+func (g *Gazetteer) CountryByCode(code string) (Country, bool) {
+	c, ok := g.countries[code]
+	return c, ok
+}
+
+// City returns the city with the given name or alias in a country.
+func (g *Gazetteer) City(name, countryCode string) (City, bool) {
+	c, ok := g.cities[cityKey(name, countryCode)]
+	return c, ok
+}
+
+// Place is a resolved location. Fields that could not be resolved are
+// empty; Lat and Lon are nil unless the city was found.
+type Place struct {
+	City    string   `json:"city,omitempty"`
+	Region  string   `json:"region,omitempty"`
+	Country string   `json:"country,omitempty"`
+	Lat     *float64 `json:"lat,omitempty"`
+	Lon     *float64 `json:"lon,omitempty"`
+}
+
+// Resolve parses a location written most specific part first and country
+// last. The country must be known; the first part is looked up as a city,
+// and a part between them names the region when the gazetteer does not.
+// Constituent countries such as "England" resolve to their state ("GB")
+// and become the region.
+func (g *Gazetteer) Resolve(location string) (Place, error) {
+	var parts []string
+	for _, p := range strings.Split(location, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return Place{}, fmt.Errorf("empty location")
+	}
+	last := parts[len(parts)-1]
+	country, ok := g.Country(last)
+	if !ok {
+		return Place{}, fmt.Errorf("unknown country %q", last)
+	}
+	place := Place{Country: country.Code}
+	if isConstituent(last) {
+		place.Region = last
+	}
+	if len(parts) == 1 {
+		return place, nil
+	}
+	if len(parts) > 2 {
+		place.Region = parts[len(parts)-2]
+	}
+	city, ok := g.City(parts[0], country.Code)
+	if !ok {
+		return place, fmt.Errorf("unknown city %q in %s", parts[0], country.Code)
+	}
+	place.City = city.Name
+	if city.Region != "" {
+		place.Region = city.Region
+	}
+	lat, lon := city.Lat, city.Lon
+	place.Lat, place.Lon = &lat, &lon
+	return place, nil
+}
+
+// isConstituent reports whether name is a constituent country of the United
+// Kingdom, which the dataset uses in place of the state.
+func isConstituent(name string) bool {
+	switch museums.Fold(name) {
+	case "england", "scotland", "wales", "northern ireland":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"testing"
+)
+
+const (
+	testCountries = `code,name,aliases
+GB,United Kingdom,UK|England|Scotland|Wales|Northern Ireland
+DE,Germany,Deutschland
+US,United States,USA|United States of America
+`
+	testCities = `name,aliases,region,country,lat,lon
+London,,England,GB,51.51,-0.13
+Edinburgh,,,GB,55.95,-3.19
+Köln,Cologne,North Rhine-Westphalia,DE,50.94,6.96
+Kodiak,,Alaska,US,57.79,-152.41
+`
+)
+
+func TestResolve(t *testing.T) {
+	g, err := New(testCountries, testCities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		location           string
+		city, region, code string
+		lat, lon           float64
+		err                string
+	}{
+		{"Kodiak, Alaska, USA", "Kodiak", "Alaska", "US", 57.79, -152.41, ""},
+		{"kodiak , united states of america", "Kodiak", "Alaska", "US", 57.79, -152.41, ""},
+		// Aliases of cities and countries, ignoring case and accents.
+		{"Cologne, Deutschland", "Köln", "North Rhine-Westphalia", "DE", 50.94, 6.96, ""},
+		{"KOLN, germany", "Köln", "North Rhine-Westphalia", "DE", 50.94, 6.96, ""},
+		// Constituent countries resolve to GB and become the region,
+		// unless the gazetteer has one for the city.
+		{"London, England", "London", "England", "GB", 51.51, -0.13, ""},
+		{"Edinburgh, Scotland", "Edinburgh", "Scotland", "GB", 55.95, -3.19, ""},
+		{"Edinburgh, UK", "Edinburgh", "", "GB", 55.95, -3.19, ""},
+		{"Wales", "", "Wales", "GB", 0, 0, ""},
+		{"Germany", "", "", "DE", 0, 0, ""},
+		// An unknown city keeps the country and the region as written.
+		{"Cardiff, Wales", "", "Wales", "GB", 0, 0, `unknown city "Cardiff" in GB`},
+		{"Mainz, Rhineland-Palatinate, Germany", "", "Rhineland-Palatinate", "DE", 0, 0, `unknown city "Mainz" in DE`},
+		{"London, USA", "", "", "US", 0, 0, `unknown city "London" in US`},
+		// An unknown country resolves nothing.
+		{"Paris, France", "", "", "", 0, 0, `unknown country "France"`},
+		{" , ", "", "", "", 0, 0, "empty location"},
+	}
+	for _, tt := range tests {
+		p, err := g.Resolve(tt.location)
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("Resolve(%q) error = %v, want %q", tt.location, err, tt.err)
+		}
+		if p.City != tt.city || p.Region != tt.region || p.Country != tt.code {
+			t.Errorf("Resolve(%q) = %q, %q, %q; want %q, %q, %q", tt.location, p.City, p.Region, p.Country, tt.city, tt.region, tt.code)
+		}
+		if tt.city == "" {
+			if p.Lat != nil || p.Lon != nil {
+				t.Errorf("Resolve(%q) has coordinates without a city", tt.location)
+			}
+		} else if p.Lat == nil || p.Lon == nil || *p.Lat != tt.lat || *p.Lon != tt.lon {
+			t.Errorf("Resolve(%q) coordinates %v, %v; want %v, %v", tt.location, p.Lat, p.Lon, tt.lat, tt.lon)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string][2]string{
+		"unknown country": {testCountries, "name,aliases,region,country,lat,lon\nParis,,,FR,48.86,2.35\n"},
+		"bad latitude":    {testCountries, "name,aliases,region,country,lat,lon\nLondon,,,GB,north,-0.13\n"},
+		"missing field":   {"code,name,aliases\nGB,United Kingdom\n", testCities},
+		"missing header":  {"", testCities},
+	}
+	for desc, tables := range tests {
+		if _, err := New(tables[0], tables[1]); err == nil {
+			t.Errorf("%s: no error", desc)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	g := Default()
+	for _, name := range []string{"GB", "England", "United Kingdom", "Deutschland", "USA"} {
+		if _, ok := g.Country(name); !ok {
+			t.Errorf("Country(%q) not found", name)
+		}
+	}
+	if _, ok := g.CountryByCode("England"); ok {
+		t.Error("CountryByCode accepted a name")
+	}
+	p, err := g.Resolve("London, England")
+	if err != nil || p.Country != "GB" || p.Region != "England" || p.City != "London" {
+		t.Errorf("Resolve(London, England) = %+v, %v", p, err)
+	}
+}
@@ -0,0 +1,63 @@
+package museums
+
+// FeatureCollection is a GeoJSON (RFC 7946) feature collection of museums.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is one museum as a GeoJSON point.
+type Feature struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Geometry   Point             `json:"geometry"`
+	Properties FeatureProperties `json:"properties"`
+}
+
+// Point is a GeoJSON point. Coordinates are longitude first.
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// FeatureProperties are the museum fields a map shows.
+type FeatureProperties struct {
+	Name         string `json:"name"`
+	Category     string `json:"category,omitempty"`
+	FoundingYear int    `json:"foundingYear,omitempty"`
+	Location     string `json:"location,omitempty"`
+	City         string `json:"city,omitempty"`
+	Region       string `json:"region,omitempty"`
+	Country      string `json:"country,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// GeoJSON returns the museums that have coordinates as a feature
+// collection, in the order given. The number of museums left out for lack
+// of coordinates is returned too.
+func GeoJSON(list []Museum) (FeatureCollection, int) {
+	fc := FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	skipped := 0
+	for _, m := range list {
+		if m.Lat == nil || m.Lon == nil {
+			skipped++
+			continue
+		}
+		fc.Features = append(fc.Features, Feature{
+			Type:     "Feature",
+			ID:       m.Slug,
+			Geometry: Point{Type: "Point", Coordinates: [2]float64{*m.Lon, *m.Lat}},
+			Properties: FeatureProperties{
+				Name:         m.Name,
+				Category:     m.Category,
+				FoundingYear: m.FoundingYear(),
+				Location:     m.Location,
+				City:         m.City(),
+				Region:       m.Region,
+				Country:      m.CountryCode,
+				URL:          m.URL,
+			},
+		})
+	}
+	return fc, skipped
+}
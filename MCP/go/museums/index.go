@@ -0,0 +1,244 @@
+package museums
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Index is an immutable, indexed view of the dataset. It is safe for
+// concurrent use.
+type Index struct {
+	museums   []Museum // sorted by slug
+	bySlug    map[string]int
+	byCountry map[string][]int
+	byCity    map[string][]int
+	// byLanguage maps ISO 639-3 codes to positions in museums.
+	byLanguage map[string][]int
+	byYear     []int // positions in museums, sorted by founding year
+	countries  []string
+}
+
+// Query combines filters; zero-valued fields are ignored.
+type Query struct {
+	// Country is case- and accent-insensitive and matches Museum.Country or
+	// the ISO code in Museum.CountryCode.
+	Country string
+	City    string // case- and accent-insensitive, matches Museum.City
+	Name    string // case- and accent-insensitive substring of Museum.Name
+	// Category is a Category ID such as "writing".
+	Category string
+	// Language is a language name or code, as accepted by ByLanguage.
+	Language string
+	// FoundedFrom and FoundedTo bound the founding year, inclusive.
+	FoundedFrom int
+	FoundedTo   int
+}
+
+// NewIndex indexes museums. Later entries with a duplicate slug are dropped.
+func NewIndex(museums []Museum) *Index {
+	ix := &Index{
+		bySlug:     make(map[string]int),
+		byCountry:  make(map[string][]int),
+		byCity:     make(map[string][]int),
+		byLanguage: make(map[string][]int),
+	}
+	for _, m := range museums {
+		if _, dup := ix.bySlug[m.Slug]; dup {
+			continue
+		}
+		ix.bySlug[m.Slug] = -1
+		ix.museums = append(ix.museums, m)
+	}
+	sort.Slice(ix.museums, func(i, j int) bool { return ix.museums[i].Slug < ix.museums[j].Slug })
+
+	countries := make(map[string]string)
+	for i, m := range ix.museums {
+		ix.bySlug[m.Slug] = i
+		if c := m.Country(); c != "" {
+			key := Fold(c)
+			ix.byCountry[key] = append(ix.byCountry[key], i)
+			if _, ok := countries[key]; !ok {
+				countries[key] = c
+			}
+		}
+		if code := Fold(m.CountryCode); code != "" && code != Fold(m.Country()) {
+			ix.byCountry[code] = append(ix.byCountry[code], i)
+		}
+		if c := m.City(); c != "" {
+			ix.byCity[Fold(c)] = append(ix.byCity[Fold(c)], i)
+		}
+		for _, code := range m.Languages {
+			ix.byLanguage[code] = append(ix.byLanguage[code], i)
+		}
+		if m.FoundingYear() != 0 {
+			ix.byYear = append(ix.byYear, i)
+		}
+	}
+	sort.SliceStable(ix.byYear, func(a, b int) bool {
+		return ix.museums[ix.byYear[a]].FoundingYear() < ix.museums[ix.byYear[b]].FoundingYear()
+	})
+	for _, c := range countries {
+		ix.countries = append(ix.countries, c)
+	}
+	sort.Strings(ix.countries)
+	return ix
+}
+
+// Len returns the number of museums.
+func (ix *Index) Len() int {
+	return len(ix.museums)
+}
+
+// All returns every museum, sorted by slug.
+func (ix *Index) All() []Museum {
+	return append([]Museum(nil), ix.museums...)
+}
+
+// Get returns the museum with the given slug.
+func (ix *Index) Get(slug string) (Museum, bool) {
+	i, ok := ix.bySlug[slug]
+	if !ok {
+		return Museum{}, false
+	}
+	return ix.museums[i], true
+}
+
+// Countries returns the distinct countries in the dataset, sorted, as
+// written in the first museum that names each one.
+func (ix *Index) Countries() []string {
+	return append([]string(nil), ix.countries...)
+}
+
+// ByCountry returns the museums in country, given by name or ISO code.
+func (ix *Index) ByCountry(country string) []Museum {
+	return ix.pick(ix.byCountry[Fold(strings.TrimSpace(country))])
+}
+
+// ByCity returns the museums in city.
+func (ix *Index) ByCity(city string) []Museum {
+	return ix.pick(ix.byCity[Fold(strings.TrimSpace(city))])
+}
+
+// ByLanguage returns the museums about a language, given by ISO 639-3 or
+// 639-1 code, English name or alias: ByLanguage("Basque"), ByLanguage("eu")
+// and ByLanguage("eus") are the same.
+func (ix *Index) ByLanguage(language string) []Museum {
+	return ix.pick(ix.byLanguage[languageCode(language)])
+}
+
+// Languages returns the languages the museums are about, sorted by name,
+// with the number of museums about each.
+func (ix *Index) Languages() []LanguageCount {
+	var out []LanguageCount
+	for code, positions := range ix.byLanguage {
+		l, ok := LanguageByCode(code)
+		if !ok {
+			l = Language{Code: code, Name: code}
+		}
+		out = append(out, LanguageCount{Language: l, Museums: len(positions)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LanguageCount is a language with the number of museums about it.
+type LanguageCount struct {
+	Language
+	Museums int `json:"museums"`
+}
+
+// languageCode resolves a language name or code to its ISO 639-3 code. An
+// unknown value is returned folded, so that codes missing from the
+// language table still match.
+func languageCode(language string) string {
+	if l, ok := LookupLanguage(language); ok {
+		return l.Code
+	}
+	return Fold(strings.TrimSpace(language))
+}
+
+// FoundedBetween returns the museums founded from one year to another,
+// inclusive, ordered by founding year.
+func (ix *Index) FoundedBetween(from, to int) []Museum {
+	lo := sort.Search(len(ix.byYear), func(i int) bool {
+		return ix.museums[ix.byYear[i]].FoundingYear() >= from
+	})
+	hi := sort.Search(len(ix.byYear), func(i int) bool {
+		return ix.museums[ix.byYear[i]].FoundingYear() > to
+	})
+	if hi < lo {
+		return nil
+	}
+	return ix.pick(ix.byYear[lo:hi])
+}
+
+// ByCategory returns the museums in the category with the given ID, sorted
+// by slug.
+func (ix *Index) ByCategory(id string) []Museum {
+	var out []Museum
+	for _, m := range ix.museums {
+		if m.Category == id {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// SearchName returns the museums whose name contains substr, ignoring case
+// and accents.
+func (ix *Index) SearchName(substr string) []Museum {
+	needle := Fold(strings.TrimSpace(substr))
+	var out []Museum
+	for _, m := range ix.museums {
+		if strings.Contains(Fold(m.Name), needle) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Find returns the museums matching every non-zero field of q, sorted by
+// slug.
+func (ix *Index) Find(q Query) []Museum {
+	var out []Museum
+	for _, m := range ix.museums {
+		if q.matches(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (q Query) matches(m Museum) bool {
+	if c := Fold(strings.TrimSpace(q.Country)); c != "" && Fold(m.Country()) != c && Fold(m.CountryCode) != c {
+		return false
+	}
+	if q.City != "" && Fold(m.City()) != Fold(strings.TrimSpace(q.City)) {
+		return false
+	}
+	if q.Category != "" && m.Category != strings.TrimSpace(q.Category) {
+		return false
+	}
+	if q.Language != "" && !slices.Contains(m.Languages, languageCode(q.Language)) {
+		return false
+	}
+	if q.Name != "" && !strings.Contains(Fold(m.Name), Fold(strings.TrimSpace(q.Name))) {
+		return false
+	}
+	if q.FoundedFrom != 0 || q.FoundedTo != 0 {
+		y := m.FoundingYear()
+		if y == 0 || (q.FoundedFrom != 0 && y < q.FoundedFrom) || (q.FoundedTo != 0 && y > q.FoundedTo) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ix *Index) pick(positions []int) []Museum {
+	out := make([]Museum, len(positions))
+	for i, p := range positions {
+		out[i] = ix.museums[p]
+	}
+	return out
+}
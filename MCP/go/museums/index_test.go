@@ -0,0 +1,250 @@
+package museums
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func testIndex() *Index {
+	return NewIndex([]Museum{
+		{Slug: "gutenberg-museum", Name: "Gutenberg Museum", LastUpdated: "1900", Category: "writing", Location: "Mainz, Germany", CountryCode: "DE"},
+		{Slug: "grimmwelt", Name: "Grimmwelt", LastUpdated: "2015", Category: "persons", Location: "Kassel, Germany", Languages: []string{"deu"}},
+		{Slug: "euskararen-museoa", Name: "Euskararen Museoa", Names: map[string]string{"eu": "Euskararen Etxea"}, LastUpdated: "2009", Category: "one-language", Location: "Bilbao, Spain", Languages: []string{"eus"}},
+		{Slug: "culturlann-mcadam-o-fiaich", Name: "Cultúrlann McAdam Ó Fiaich", LastUpdated: "1991", Category: "one-language", Location: "Belfast, Northern Ireland, UK", Languages: []string{"gle"}, Address: "216 Falls Road"},
+		{Slug: "mundolingua", Name: "Mundolingua", LastUpdated: "2013", Category: "languages-of-the-world", Location: "Paris, France"},
+		{Slug: "museum-of-the-future", Name: "Museum of the Future", Category: Planned, Location: "Mainz, Germany"},
+		// Dropped: same slug as an earlier entry.
+		{Slug: "grimmwelt", Name: "Brothers Grimm Museum", LastUpdated: "1959", Location: "Kassel, Germany"},
+	})
+}
+
+func names(list []Museum) []string {
+	var out []string
+	for _, m := range list {
+		out = append(out, m.Name)
+	}
+	return out
+}
+
+func TestNewIndex(t *testing.T) {
+	ix := testIndex()
+	if ix.Len() != 6 {
+		t.Errorf("Len = %d, want 6", ix.Len())
+	}
+	if m, ok := ix.Get("grimmwelt"); !ok || m.Name != "Grimmwelt" {
+		t.Errorf("Get(grimmwelt) = %q, %v; want the first entry", m.Name, ok)
+	}
+	if _, ok := ix.Get("nope"); ok {
+		t.Error("Get(nope) found a museum")
+	}
+	want := []string{"France", "Germany", "Spain", "UK"}
+	if got := ix.Countries(); !slices.Equal(got, want) {
+		t.Errorf("Countries = %q, want %q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		desc string
+		q    Query
+		want []string
+	}{
+		{"everything", Query{}, []string{"Cultúrlann McAdam Ó Fiaich", "Euskararen Museoa", "Grimmwelt", "Gutenberg Museum", "Mundolingua", "Museum of the Future"}},
+		{"country name", Query{Country: " germany "}, []string{"Grimmwelt", "Gutenberg Museum", "Museum of the Future"}},
+		{"country code", Query{Country: "de"}, []string{"Gutenberg Museum"}},
+		{"city", Query{City: "MAINZ"}, []string{"Gutenberg Museum", "Museum of the Future"}},
+		{"category", Query{Category: "one-language"}, []string{"Cultúrlann McAdam Ó Fiaich", "Euskararen Museoa"}},
+		{"language name", Query{Language: "Basque"}, []string{"Euskararen Museoa"}},
+		{"language alias", Query{Language: "Gaeilge"}, []string{"Cultúrlann McAdam Ó Fiaich"}},
+		{"name", Query{Name: "culturlann"}, []string{"Cultúrlann McAdam Ó Fiaich"}},
+		{"founded", Query{FoundedFrom: 2000}, []string{"Euskararen Museoa", "Grimmwelt", "Mundolingua"}},
+		{"founded and country", Query{FoundedTo: 2010, Country: "Germany"}, []string{"Gutenberg Museum"}},
+		{"no match", Query{Country: "Germany", Category: "one-language"}, nil},
+	}
+	for _, tt := range tests {
+		if got := names(ix.Find(tt.q)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		text string
+		q    Query
+		want []string
+	}{
+		// Prefixes of a name word score 1, typos less, other fields 0.5;
+		// ties keep the slug order.
+		{"museum", Query{}, []string{"Gutenberg Museum", "Museum of the Future", "Euskararen Museoa"}},
+		{"gutenburg", Query{}, []string{"Gutenberg Museum"}},
+		{"mainz", Query{}, []string{"Gutenberg Museum", "Museum of the Future"}},
+		{"mainz museum", Query{}, []string{"Gutenberg Museum", "Museum of the Future"}},
+		{"future mainz", Query{}, []string{"Museum of the Future"}},
+		{"etxea", Query{}, []string{"Euskararen Museoa"}},
+		{"irish falls", Query{}, []string{"Cultúrlann McAdam Ó Fiaich"}},
+		{"Cultúrlann", Query{}, []string{"Cultúrlann McAdam Ó Fiaich"}},
+		{"museum", Query{Category: "writing"}, []string{"Gutenberg Museum"}},
+		// Every term must match.
+		{"gutenberg paris", Query{}, nil},
+		// Short terms are not matched fuzzily.
+		{"mun", Query{}, []string{"Mundolingua"}},
+		{"mux", Query{}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range ix.Search(tt.text, tt.q) {
+			got = append(got, m.Museum.Name)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q, %+v) = %q, want %q", tt.text, tt.q, got, tt.want)
+		}
+	}
+
+	// An exact prefix outranks a typo, and a name outranks the address.
+	scores := map[string]float64{}
+	for _, text := range []string{"gutenberg", "gutenburg", "mainz"} {
+		for _, m := range ix.Search(text, Query{Name: "Gutenberg"}) {
+			scores[text] = m.Score
+		}
+	}
+	if !(scores["gutenberg"] == 1 && scores["gutenburg"] < 1 && scores["gutenburg"] > 0.5 && scores["mainz"] == 0.5) {
+		t.Errorf("scores %v", scores)
+	}
+	if got := len(ix.Search("", Query{})); got != ix.Len() {
+		t.Errorf("empty search returned %d museums, want %d", got, ix.Len())
+	}
+}
+
+func TestFoundedBetween(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		from, to int
+		want     []string
+	}{
+		{1900, 2015, []string{"Gutenberg Museum", "Cultúrlann McAdam Ó Fiaich", "Euskararen Museoa", "Mundolingua", "Grimmwelt"}},
+		{1991, 2009, []string{"Cultúrlann McAdam Ó Fiaich", "Euskararen Museoa"}},
+		{2009, 2009, []string{"Euskararen Museoa"}},
+		{2016, 3000, nil},
+		{2015, 1900, nil},
+	}
+	for _, tt := range tests {
+		if got := names(ix.FoundedBetween(tt.from, tt.to)); !slices.Equal(got, tt.want) {
+			t.Errorf("FoundedBetween(%d, %d) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestByCountry(t *testing.T) {
+	ix := testIndex()
+	tests := map[string][]string{
+		"Germany":   {"Grimmwelt", "Gutenberg Museum", "Museum of the Future"},
+		" GERMANY ": {"Grimmwelt", "Gutenberg Museum", "Museum of the Future"},
+		"DE":        {"Gutenberg Museum"},
+		"uk":        {"Cultúrlann McAdam Ó Fiaich"},
+		"Atlantis":  nil,
+	}
+	for country, want := range tests {
+		if got := names(ix.ByCountry(country)); !slices.Equal(got, want) {
+			t.Errorf("ByCountry(%q) = %q, want %q", country, got, want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"alutiiq-museum.json":        "alutiiq-museum",
+		"Dr. Johnson's House":        "dr-johnsons-house",
+		"Dr. Johnson’s House":        "dr-johnsons-house",
+		"Cultúrlann McAdam Ó Fiaich": "culturlann-mcadam-o-fiaich",
+		"Straße der Sprache":         "strasse-der-sprache",
+		"  --Mundolingua--  ":        "mundolingua",
+		"Музей":                      "",
+	}
+	for in, want := range tests {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Cultúrlann":  "culturlann",
+		"Gàidheal":    "gaidheal",
+		"Straße":      "strasse",
+		"ÆSIR Œuvre":  "aesir oeuvre",
+		"Музей Ёлки":  "музей ёлки",
+		"plain ASCII": "plain ascii",
+	}
+	for in, want := range tests {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := testIndex()
+	tests := []struct {
+		slug string
+		n    int
+		want []string
+	}{
+		{"grimwelt", 3, []string{"grimmwelt"}},
+		{"Gutenberg", 3, []string{"gutenberg-museum"}},
+		{"museum", 3, []string{"gutenberg-museum", "museum-of-the-future"}},
+		{"museum", 1, []string{"gutenberg-museum"}},
+		{"mundolinga", 3, []string{"mundolingua"}},
+		{"zzzzzz", 3, nil},
+	}
+	for _, tt := range tests {
+		if got := ix.Suggest(tt.slug, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %q, want %q", tt.slug, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestEmbeddedCopy checks that go generate has been run since the dataset
+// last changed.
+func TestEmbeddedCopy(t *testing.T) {
+	source, err := filepath.Glob("../../../museums-json/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := filepath.Glob("data/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(source) == 0 {
+		t.Fatal("no files in museums-json")
+	}
+	base := func(paths []string) []string {
+		out := make([]string, len(paths))
+		for i, p := range paths {
+			out[i] = filepath.Base(p)
+		}
+		return out
+	}
+	if !slices.Equal(base(source), base(copied)) {
+		t.Fatalf("data has different files from museums-json; run go generate ./museums")
+	}
+	for _, p := range source {
+		want, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join("data", filepath.Base(p)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("data/%s differs from museums-json; run go generate ./museums", filepath.Base(p))
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package jsondoc edits the files of the museums-json dataset without
+// disturbing them: it keeps the order of the top-level keys and writes the
+// format the files were created in, and it records where each value is so
+// that tools can report problems by line.
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is one decoded museum file.
+type Document struct {
+	data   []byte
+	value  map[string]any
+	fields []field
+	// offsets maps the JSON pointer of each value to the byte offset just
+	// past its key, or past its first token for array items.
+	offsets map[string]int64
+}
+
+type field struct {
+	key   string
+	value json.RawMessage
+}
+
+// Parse decodes data, which must hold a single JSON object. On a syntax
+// error it also returns the line of the error.
+func Parse(data []byte) (*Document, int, error) {
+	doc := &Document{data: data, offsets: map[string]int64{}}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := doc.walk(dec, "")
+	if err == nil {
+		if _, extra := dec.Token(); extra != io.EOF {
+			err = errors.New("unexpected data after the top-level value")
+		}
+	}
+	if err != nil {
+		offset := dec.InputOffset()
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			offset = syntax.Offset
+		}
+		return nil, doc.line(offset), err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, 1, fmt.Errorf("top-level value must be an object")
+	}
+	doc.value = obj
+
+	// A second pass keeps the raw top-level values in file order.
+	dec = json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, 1, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, 1, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, 1, err
+		}
+		doc.fields = append(doc.fields, field{key: tok.(string), value: raw})
+	}
+	return doc, 0, nil
+}
+
+// walk decodes the next value, recording the offset of every nested value.
+func (d *Document) walk(dec *json.Decoder, ptr string) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := d.offsets[ptr]; !ok {
+		d.offsets[ptr] = dec.InputOffset()
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := map[string]any{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name := key.(string)
+			child := ptr + "/" + EscapePointer(name)
+			d.offsets[child] = dec.InputOffset()
+			if obj[name], err = d.walk(dec, child); err != nil {
+				return nil, err
+			}
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for i := 0; dec.More(); i++ {
+			item, err := d.walk(dec, ptr+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, item)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// EscapePointer escapes a key for use in a JSON pointer (RFC 6901).
+func EscapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// Value returns the decoded object, with numbers as json.Number.
+func (d *Document) Value() map[string]any {
+	return d.value
+}
+
+// Keys returns the top-level keys in file order.
+func (d *Document) Keys() []string {
+	keys := make([]string, len(d.fields))
+	for i, f := range d.fields {
+		keys[i] = f.key
+	}
+	return keys
+}
+
+// String returns the top-level string value of key.
+func (d *Document) String(key string) (string, bool) {
+	s, ok := d.value[key].(string)
+	return s, ok
+}
+
+// Has reports whether the document has a top-level key.
+func (d *Document) Has(key string) bool {
+	_, ok := d.value[key]
+	return ok
+}
+
+func (d *Document) line(offset int64) int {
+	if offset > int64(len(d.data)) {
+		offset = int64(len(d.data))
+	}
+	return 1 + bytes.Count(d.data[:offset], []byte("\n"))
+}
+
+// Line returns the 1-based line of the value at the JSON pointer ptr, or of
+// its closest ancestor that was in the parsed file.
+func (d *Document) Line(ptr string) int {
+	for {
+		if off, ok := d.offsets[ptr]; ok {
+			return d.line(off)
+		}
+		i := strings.LastIndexByte(ptr, '/')
+		if i < 0 {
+			return 1
+		}
+		ptr = ptr[:i]
+	}
+}
+
+// Set replaces the top-level value of key. A new key is inserted after the
+// last of the keys in after that the document has, or at the end.
+func (d *Document) Set(key string, v any, after ...string) error {
+	raw, err := Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	var decoded any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	d.value[key] = decoded
+
+	for i, f := range d.fields {
+		if f.key == key {
+			d.fields[i].value = raw
+			return nil
+		}
+	}
+	pos := len(d.fields)
+	for i, f := range d.fields {
+		for _, a := range after {
+			if f.key == a {
+				pos = i + 1
+			}
+		}
+	}
+	d.fields = append(d.fields, field{})
+	copy(d.fields[pos+1:], d.fields[pos:])
+	d.fields[pos] = field{key: key, value: raw}
+	return nil
+}
+
+// Delete removes a top-level key.
+func (d *Document) Delete(key string) {
+	delete(d.value, key)
+	for i, f := range d.fields {
+		if f.key == key {
+			d.fields = append(d.fields[:i], d.fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// Encode writes the document the way the dataset's files are written:
+// two-space indentation, non-ASCII characters escaped and no final newline.
+func (d *Document) Encode() ([]byte, error) {
+	var compact bytes.Buffer
+	compact.WriteByte('{')
+	for i, f := range d.fields {
+		if i > 0 {
+			compact.WriteByte(',')
+		}
+		key, _ := Marshal(f.key)
+		compact.Write(key)
+		compact.WriteByte(':')
+		if err := json.Compact(&compact, f.value); err != nil {
+			return nil, err
+		}
+	}
+	compact.WriteByte('}')
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Marshal encodes v as compact JSON with every non-ASCII character escaped
+// as \uXXXX, matching the files written by the original splitter.
+func Marshal(v any) ([]byte, error) {
+	var enc bytes.Buffer
+	e := json.NewEncoder(&enc)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	plain := bytes.TrimSuffix(enc.Bytes(), []byte("\n"))
+
+	// Outside strings JSON is ASCII, so every other rune is inside one.
+	var b bytes.Buffer
+	for len(plain) > 0 {
+		r, size := utf8.DecodeRune(plain)
+		plain = plain[size:]
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case r > 0xffff:
+			r -= 0x10000
+			fmt.Fprintf(&b, `\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+package museums
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed languages.csv
+var languagesCSV string
+
+// Language is an ISO 639-3 language.
+type Language struct {
+	Code  string `json:"code"`            // ISO 639-3, e.g. "eus"
+	Part1 string `json:"part1,omitempty"` // ISO 639-1, if the language has one, e.g. "eu"
+	Name  string `json:"name"`            // English name, e.g. "Basque"
+}
+
+// The language table covers the languages named in the dataset and other
+// widely spoken ones; add a line to languages.csv when a museum documents a
+// language that is not in it.
+var (
+	languagesOnce   sync.Once
+	languagesByCode map[string]Language // by ISO 639-3 code
+	languagesByTag  map[string]Language // by ISO 639-3 or ISO 639-1 code
+	languagesByName map[string]Language // by folded code, name or alias
+)
+
+func loadLanguages() {
+	languagesOnce.Do(func() {
+		rows, err := csv.NewReader(strings.NewReader(languagesCSV)).ReadAll()
+		if err != nil || len(rows) == 0 {
+			panic(fmt.Sprintf("museums: languages.csv: %v", err))
+		}
+		languagesByCode = map[string]Language{}
+		languagesByTag = map[string]Language{}
+		languagesByName = map[string]Language{}
+		for _, r := range rows[1:] {
+			l := Language{Code: r[0], Part1: r[1], Name: r[2]}
+			languagesByCode[l.Code] = l
+			languagesByTag[l.Code] = l
+			keys := []string{l.Code, l.Name}
+			if l.Part1 != "" {
+				languagesByTag[l.Part1] = l
+				keys = append(keys, l.Part1)
+			}
+			if r[3] != "" {
+				keys = append(keys, strings.Split(r[3], "|")...)
+			}
+			for _, k := range keys {
+				languagesByName[Fold(k)] = l
+			}
+		}
+	})
+}
+
+// LookupLanguage returns the language with the given ISO 639-3 code, ISO
+// 639-1 code, English name or alias, such as "eus", "eu", "Basque" or
+// "Euskara". Case and accents are ignored.
+func LookupLanguage(nameOrCode string) (Language, bool) {
+	loadLanguages()
+	l, ok := languagesByName[Fold(strings.TrimSpace(nameOrCode))]
+	return l, ok
+}
+
+// LanguageByCode returns the language with the given ISO 639-3 code only.
+func LanguageByCode(code string) (Language, bool) {
+	loadLanguages()
+	l, ok := languagesByCode[code]
+	return l, ok
+}
+
+// TagLanguage returns the language of a BCP 47 tag such as "zh-Hans" or
+// "sr-Cyrl", from its primary subtag.
+func TagLanguage(tag string) (Language, bool) {
+	primary, _, _ := strings.Cut(tag, "-")
+	loadLanguages()
+	l, ok := languagesByTag[strings.ToLower(primary)]
+	return l, ok
+}
+
+// LanguageList returns the museum's languages, in the order of Languages.
+// Codes missing from the language table are returned with an empty name.
+func (m Museum) LanguageList() []Language {
+	out := make([]Language, 0, len(m.Languages))
+	for _, code := range m.Languages {
+		l, ok := LanguageByCode(code)
+		if !ok {
+			l = Language{Code: code}
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// NameIn returns the museum's name in the language of tag, matching the
+// tag exactly or else by its language, and falling back to Name.
+func (m Museum) NameIn(tag string) string {
+	if n, ok := m.Names[tag]; ok {
+		return n
+	}
+	want, ok := TagLanguage(tag)
+	if !ok {
+		return m.Name
+	}
+	tags := make([]string, 0, len(m.Names))
+	for t := range m.Names {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	for _, t := range tags {
+		if l, ok := TagLanguage(t); ok && l.Code == want.Code {
+			return m.Names[t]
+		}
+	}
+	return m.Name
+}
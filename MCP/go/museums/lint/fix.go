@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/museums/jsondoc"
+)
+
+// Fix rewrites the files in dir to correct the problems marked fixable:
+// surrounding whitespace, bare URLs, irregular phone spacing and file names
+// that are not slugs. It never changes what a value means, keeps the order
+// of keys and the file format, and does not rename over an existing file.
+// It returns one line per change made.
+func Fix(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		name := filepath.Join(dir, e.Name())
+		fileChanges, err := fixFile(name)
+		if err != nil {
+			return changes, err
+		}
+		changes = append(changes, fileChanges...)
+	}
+	return changes, nil
+}
+
+func fixFile(name string) ([]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	note := func(format string, args ...any) {
+		changes = append(changes, name+": "+fmt.Sprintf(format, args...))
+	}
+
+	// Files that are not valid JSON are left for a person to repair.
+	if doc, _, err := jsondoc.Parse(data); err == nil {
+		for _, key := range doc.Keys() {
+			s, ok := doc.String(key)
+			if !ok {
+				continue
+			}
+			fixed := strings.TrimSpace(s)
+			switch key {
+			case "url":
+				fixed, _ = FixURL(fixed)
+			case "phone":
+				fixed = FixPhone(fixed)
+			}
+			if fixed != s {
+				if err := doc.Set(key, fixed); err != nil {
+					return nil, err
+				}
+				note("%s: %q -> %q", key, s, fixed)
+			}
+		}
+		out, err := doc.Encode()
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 && !bytes.Equal(out, data) {
+			if err := os.WriteFile(name, out, 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	base := filepath.Base(name)
+	want := museums.Slugify(base) + ".json"
+	if want == base {
+		return changes, nil
+	}
+	target := filepath.Join(filepath.Dir(name), want)
+	if _, err := os.Stat(target); err == nil {
+		note("not renamed: %s already exists", want)
+		return changes, nil
+	}
+	if err := os.Rename(name, target); err != nil {
+		return changes, err
+	}
+	note("renamed to %s", want)
+	return changes, nil
+}
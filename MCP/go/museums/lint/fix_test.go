@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFixURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"www.grimms.de/museum", "http://www.grimms.de/museum", true},
+		{"https://www.grimms.de", "https://www.grimms.de", false},
+		{"info@grimms.de", "info@grimms.de", false},
+		{"a.example.org b.example.org", "a.example.org b.example.org", false},
+		{"grimms", "grimms", false},
+	}
+	for _, tt := range tests {
+		if got, ok := FixURL(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("FixURL(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFixPhone(t *testing.T) {
+	tests := map[string]string{
+		"+49 6131 122503":          "+49 6131 122503",
+		" + 49  6131\t122503 ":     "+49 6131 122503",
+		"+421 43 49 44 244 or\n43": "+421 43 49 44 244 or 43",
+	}
+	for in, want := range tests {
+		if got := FixPhone(in); got != want {
+			t.Errorf("FixPhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Every fixable problem in one file, which is also renamed.
+		"Gutenberg Museum.json": museum(`"url": "www.gutenberg-museum.de", "phone": "+ 49  6131 122503", "address": " Liebfrauenplatz 5"`),
+		// Problems that need a person are left alone.
+		"grimmwelt.json": strings.Replace(museum(`"url": "http://a.example.org http://b.example.org"`), "Gutenberg Museum", "Grimmwelt", 1),
+		// The rename would overwrite this file.
+		"Klingspor Museum.json": strings.Replace(museum(""), "Gutenberg Museum", "Klingspor Museum", 1),
+		"klingspor-museum.json": strings.Replace(museum(""), "Gutenberg Museum", "Klingspor Museum", 1),
+		// Invalid JSON is only renamed.
+		"Broken.json": "{",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := Fix(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range changes {
+		changes[i] = strings.TrimPrefix(c, dir+string(filepath.Separator))
+	}
+	want := []string{
+		"Broken.json: renamed to broken.json",
+		`Gutenberg Museum.json: url: "www.gutenberg-museum.de" -> "http://www.gutenberg-museum.de"`,
+		`Gutenberg Museum.json: phone: "+ 49  6131 122503" -> "+49 6131 122503"`,
+		`Gutenberg Museum.json: address: " Liebfrauenplatz 5" -> "Liebfrauenplatz 5"`,
+		"Gutenberg Museum.json: renamed to gutenberg-museum.json",
+		"Klingspor Museum.json: not renamed: klingspor-museum.json already exists",
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("Fix:\ngot  %q\nwant %q", changes, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "gutenberg-museum.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := strings.Join([]string{
+		`{`,
+		`  "url": "http://www.gutenberg-museum.de",`,
+		`  "phone": "+49 6131 122503",`,
+		`  "address": "Liebfrauenplatz 5",`,
+		`  "name": "Gutenberg Museum",`,
+		`  "last_updated": "1900",`,
+		`  "category": "writing",`,
+		`  "location": "Mainz, Germany"`,
+		`}`,
+	}, "\n")
+	if string(data) != wantData {
+		t.Errorf("fixed file:\n%s\nwant:\n%s", data, wantData)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "grimmwelt.json")); string(data) != files["grimmwelt.json"] {
+		t.Errorf("file without fixable problems changed:\n%s", data)
+	}
+
+	// A second run has nothing left to fix.
+	if changes, err := Fix(dir); err != nil || len(changes) != 1 {
+		t.Errorf("second Fix = %q, %v, want only the blocked rename", changes, err)
+	}
+	diags, err := New().Dir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range diags {
+		if d.Fixable && !strings.HasSuffix(d.File, "Klingspor Museum.json") {
+			t.Errorf("fixable problem left: %s", d)
+		}
+	}
+}
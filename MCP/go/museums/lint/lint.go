@@ -0,0 +1,404 @@
+// Package lint validates the files of the museums-json directory against
+// the dataset's JSON Schema and checks what the schema cannot express: that
+// file names are slugs of the museum names, that contact details are well
+// formed and that no museum is listed twice.
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"net/mail"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/museums/geo"
+	"github.com/wordnik/mcp-server/museums/jsondoc"
+)
+
+// Severity tells whether a diagnostic fails the check.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is one problem found in a file. Line is 1-based. Field is the
+// JSON pointer of the offending value, or "" for problems with the file as
+// a whole.
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Field    string   `json:"field,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Fixable is set when Fix can correct the problem.
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+// String formats d as "file:line: field: message", the form editors and CI
+// logs link to.
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d: ", d.File, d.Line)
+	if d.Severity == SeverityWarning {
+		b.WriteString("warning: ")
+	}
+	if d.Field != "" {
+		b.WriteString(strings.TrimPrefix(d.Field, "/") + ": ")
+	}
+	b.WriteString(d.Message)
+	if d.Fixable {
+		b.WriteString(" (fixable)")
+	}
+	return b.String()
+}
+
+// HasErrors reports whether any diagnostic is an error.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Linter checks museum files against a schema.
+type Linter struct {
+	Schema *Schema
+}
+
+// New returns a Linter using the embedded schema.
+func New() *Linter {
+	return &Linter{Schema: DefaultSchema()}
+}
+
+// Dir lints every *.json file in dir. File names in the diagnostics are
+// joined to dir.
+func (l *Linter) Dir(dir string) ([]Diagnostic, error) {
+	diags, err := l.FS(os.DirFS(dir))
+	for i := range diags {
+		diags[i].File = path.Join(dir, diags[i].File)
+	}
+	return diags, err
+}
+
+// FS lints every *.json file at the root of fsys, including the checks
+// across files.
+func (l *Linter) FS(fsys fs.FS) ([]Diagnostic, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var diags []Diagnostic
+	names := map[string][]string{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		fileDiags, doc := l.file(e.Name(), data)
+		diags = append(diags, fileDiags...)
+		if doc != nil {
+			// Museums may share a name in different countries, like the
+			// Esperanto museums in China and the Czech Republic.
+			if name, ok := doc.String("name"); ok && strings.TrimSpace(name) != "" {
+				location, _ := doc.String("location")
+				key := museums.Slugify(name) + "/" + museums.Slugify(museums.Museum{Location: location}.Country())
+				names[key] = append(names[key], e.Name())
+			}
+		}
+	}
+	for _, files := range names {
+		if len(files) < 2 {
+			continue
+		}
+		for _, f := range files {
+			others := make([]string, 0, len(files)-1)
+			for _, o := range files {
+				if o != f {
+					others = append(others, o)
+				}
+			}
+			diags = append(diags, Diagnostic{
+				File: f, Line: 1, Field: "/name", Severity: SeverityError,
+				Message: "same museum name and country as " + strings.Join(others, ", "),
+			})
+		}
+	}
+	sortDiagnostics(diags)
+	return diags, nil
+}
+
+// File lints the contents of a single file.
+func (l *Linter) File(name string, data []byte) []Diagnostic {
+	diags, _ := l.file(name, data)
+	sortDiagnostics(diags)
+	return diags
+}
+
+func (l *Linter) file(name string, data []byte) ([]Diagnostic, *jsondoc.Document) {
+	var diags []Diagnostic
+	add := func(line int, field string, sev Severity, fixable bool, format string, args ...any) {
+		diags = append(diags, Diagnostic{File: name, Line: line, Field: field, Severity: sev, Message: fmt.Sprintf(format, args...), Fixable: fixable})
+	}
+
+	if want := museums.Slugify(name) + ".json"; want != name {
+		add(1, "", SeverityError, true, "file name is not a slug; want %s", want)
+	}
+
+	doc, line, err := jsondoc.Parse(data)
+	if err != nil {
+		add(line, "", SeverityError, false, "invalid JSON: %v", err)
+		return diags, nil
+	}
+	for _, v := range l.Schema.Validate(doc.Value()) {
+		add(doc.Line(v.Pointer), v.Pointer, SeverityError, false, "%s", v.Message)
+	}
+
+	for _, key := range doc.Keys() {
+		s, ok := doc.String(key)
+		if ok && s != strings.TrimSpace(s) {
+			add(doc.Line("/"+key), "/"+key, SeverityWarning, true, "leading or trailing whitespace")
+		}
+	}
+
+	if n, ok := doc.String("name"); ok && strings.TrimSpace(n) != "" {
+		location, _ := doc.String("location")
+		if slug := museums.Slugify(name); !nameMatchesSlug(n, location, slug) {
+			add(doc.Line("/name"), "/name", SeverityWarning, false, "name does not match file name; its slug is %s", museums.Slugify(n))
+		}
+	}
+	if names, ok := doc.Value()["names"].(map[string]any); ok {
+		for tag := range names {
+			ptr := "/names/" + jsondoc.EscapePointer(tag)
+			if _, ok := museums.TagLanguage(tag); !ok {
+				add(doc.Line(ptr), ptr, SeverityError, false, "unknown language in tag %q; add it to museums/languages.csv", tag)
+			}
+		}
+	}
+	if codes, ok := doc.Value()["languages"].([]any); ok {
+		for i, c := range codes {
+			ptr := fmt.Sprintf("/languages/%d", i)
+			if code, ok := c.(string); ok && len(code) == 3 {
+				if _, ok := museums.LanguageByCode(code); !ok {
+					add(doc.Line(ptr), ptr, SeverityError, false, "unknown ISO 639-3 code %q; add it to museums/languages.csv", code)
+				}
+			}
+		}
+	}
+	if code, ok := doc.String("country"); ok {
+		for _, p := range checkCountry(code, location(doc)) {
+			add(doc.Line("/country"), "/country", p.severity, p.fixable, "%s", p.message)
+		}
+	}
+	if u, ok := doc.String("url"); ok {
+		for _, p := range checkURL(u) {
+			add(doc.Line("/url"), "/url", p.severity, p.fixable, "%s", p.message)
+		}
+	}
+	if e, ok := doc.String("email"); ok {
+		for _, p := range checkEmail(e) {
+			add(doc.Line("/email"), "/email", p.severity, p.fixable, "%s", p.message)
+		}
+	}
+	for _, list := range []struct {
+		key   string
+		check func(string) []problem
+	}{{"additionalUrls", checkURL}, {"additionalEmails", checkEmail}} {
+		items, _ := doc.Value()[list.key].([]any)
+		for i, item := range items {
+			ptr := fmt.Sprintf("/%s/%d", list.key, i)
+			if s, ok := item.(string); ok {
+				for _, p := range list.check(s) {
+					add(doc.Line(ptr), ptr, p.severity, p.fixable, "%s", p.message)
+				}
+			}
+		}
+	}
+	if ph, ok := doc.String("phone"); ok {
+		for _, p := range checkPhone(ph) {
+			add(doc.Line("/phone"), "/phone", p.severity, p.fixable, "%s", p.message)
+		}
+	}
+	return diags, doc
+}
+
+// nameMatchesSlug reports whether a file slug fits the museum name. The
+// file slug may extend the name's slug with part of the location, to tell
+// apart museums of the same name (esperanto-museum-czech-republic). Names
+// partly in scripts Slugify cannot transliterate, such as "Nüshu Museum
+// (女书)", produce a shorter slug, which the file slug may extend freely.
+func nameMatchesSlug(name, location, slug string) bool {
+	want := museums.Slugify(name)
+	if want == slug {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, want+"-")
+	if !ok || want == "" {
+		return false
+	}
+	if hasUnfoldable(name) {
+		return true
+	}
+	for _, part := range strings.Split(location, ",") {
+		if museums.Slugify(part) == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUnfoldable(s string) bool {
+	for _, r := range museums.Fold(s) {
+		if r >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+func location(doc *jsondoc.Document) string {
+	s, _ := doc.String("location")
+	return s
+}
+
+// checkCountry requires a known ISO 3166-1 code that agrees with the
+// country named in location.
+func checkCountry(code, location string) []problem {
+	g := geo.Default()
+	if _, ok := g.CountryByCode(code); !ok {
+		return []problem{errorf(false, "%q is not an ISO 3166-1 alpha-2 code", code)}
+	}
+	if location == "" {
+		return nil
+	}
+	// Resolve reports the country even when the city is unknown.
+	if place, _ := g.Resolve(location); place.Country != "" && place.Country != code {
+		return []problem{errorf(false, "%s does not match location %q (%s)", code, location, place.Country)}
+	}
+	return nil
+}
+
+type problem struct {
+	severity Severity
+	fixable  bool
+	message  string
+}
+
+func errorf(fixable bool, format string, args ...any) problem {
+	return problem{severity: SeverityError, fixable: fixable, message: fmt.Sprintf(format, args...)}
+}
+
+// checkURL requires a single absolute http(s) URL with a dotted host.
+func checkURL(raw string) []problem {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return []problem{errorf(false, "empty URL")}
+	}
+	if strings.ContainsAny(s, " \t\n") {
+		return []problem{errorf(false, "holds several values; keep one URL")}
+	}
+	if !strings.Contains(s, "://") {
+		if strings.Contains(s, "@") && !strings.Contains(s, "/") {
+			return []problem{errorf(false, "%q looks like an email address", s)}
+		}
+		if fixed, ok := FixURL(s); ok {
+			return []problem{errorf(true, "missing scheme; want %s", fixed)}
+		}
+		return []problem{errorf(false, "%q is not a URL", s)}
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return []problem{errorf(false, "%v", err)}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return []problem{errorf(false, "scheme must be http or https, not %q", u.Scheme)}
+	}
+	if !validHost(u.Hostname()) {
+		return []problem{errorf(false, "invalid host %q", u.Host)}
+	}
+	return nil
+}
+
+// FixURL adds the http:// scheme to a bare URL such as www.grimms.de/museum.
+// Plain http is the safe choice: sites that serve https redirect to it.
+func FixURL(s string) (string, bool) {
+	if strings.Contains(s, "://") || strings.ContainsAny(s, " \t\n@") {
+		return s, false
+	}
+	u, err := url.Parse("http://" + s)
+	if err != nil || !validHost(u.Hostname()) {
+		return s, false
+	}
+	return "http://" + s, true
+}
+
+var hostPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+func validHost(host string) bool {
+	return hostPattern.MatchString(strings.ToLower(host))
+}
+
+// checkEmail requires a single bare address.
+func checkEmail(raw string) []problem {
+	s := strings.TrimSpace(raw)
+	if strings.ContainsAny(s, " \t\n") {
+		return []problem{errorf(false, "holds several values; keep one address")}
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return []problem{errorf(false, "%q is not an email address", s)}
+	}
+	if !validHost(s[strings.LastIndexByte(s, '@')+1:]) {
+		return []problem{errorf(false, "%q has an invalid domain", s)}
+	}
+	return nil
+}
+
+// phonePattern accepts an international number, optionally followed by
+// alternatives separated by " or ", which may omit the country code:
+// "+421 43 49 44 244 or 43 494 41 00".
+var phonePattern = regexp.MustCompile(`^\+\d[\d ()/.-]*[\d)]( or \+?\d[\d ()/.-]*[\d)])*$`)
+
+// checkPhone requires phonePattern after whitespace normalization.
+func checkPhone(raw string) []problem {
+	s := strings.TrimSpace(raw)
+	fixed := FixPhone(s)
+	if !phonePattern.MatchString(fixed) {
+		if !strings.HasPrefix(fixed, "+") {
+			return []problem{errorf(false, "%q must start with + and the country code", s)}
+		}
+		return []problem{errorf(false, "%q is not a phone number; separate alternatives with \" or \"", s)}
+	}
+	if fixed != s {
+		return []problem{{severity: SeverityWarning, fixable: true, message: fmt.Sprintf("irregular spacing; want %q", fixed)}}
+	}
+	return nil
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// FixPhone collapses runs of whitespace and removes the space in "+ 33".
+func FixPhone(s string) string {
+	s = spaces.ReplaceAllString(strings.TrimSpace(s), " ")
+	return strings.ReplaceAll(s, "+ ", "+")
+}
+
+func sortDiagnostics(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		return diags[i].Line < diags[j].Line
+	})
+}
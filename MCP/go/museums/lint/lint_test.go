@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// museum returns a valid museum file with field, a line such as
+// `"url": "x"`, inserted on line 2.
+func museum(field string) string {
+	if field != "" {
+		field = "\n  " + field + ","
+	}
+	return `{` + field + `
+  "name": "Gutenberg Museum",
+  "last_updated": "1900",
+  "category": "writing",
+  "location": "Mainz, Germany"
+}`
+}
+
+func lines(diags []Diagnostic) []string {
+	out := make([]string, len(diags))
+	for i, d := range diags {
+		out[i] = d.String()
+	}
+	return out
+}
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		desc, name, data string
+		want             []string
+	}{
+		{"valid", "gutenberg-museum.json", museum(""), nil},
+		{"valid contact details", "gutenberg-museum.json", museum(`"url": "https://www.gutenberg-museum.de/", "email": "info@example.org", "phone": "+49 6131 122503 or 6131 122640", "country": "DE"`), nil},
+
+		// File names and JSON.
+		{"file name", "Gutenberg Museum.json", museum(""),
+			[]string{"Gutenberg Museum.json:1: file name is not a slug; want gutenberg-museum.json (fixable)"}},
+		{"name", "grimmwelt.json", museum(""),
+			[]string{"grimmwelt.json:2: warning: name: name does not match file name; its slug is gutenberg-museum"}},
+		{"name and location", "gutenberg-museum-mainz.json", museum(""), nil},
+		{"invalid JSON", "gutenberg-museum.json", "{\n  \"name\": \"Gutenberg Museum\"\n  \"category\": \"writing\"\n}",
+			[]string{"gutenberg-museum.json:3: invalid JSON: invalid character '\"' after object key:value pair"}},
+
+		// Schema.
+		{"missing key", "gutenberg-museum.json", `{"name": "Gutenberg Museum", "last_updated": "1900"}`,
+			[]string{`gutenberg-museum.json:1: missing required key "category"`}},
+		{"unknown key", "gutenberg-museum.json", museum(`"website": "x"`),
+			[]string{"gutenberg-museum.json:2: website: unknown key"}},
+		{"founding year", "gutenberg-museum.json", strings.Replace(museum(""), `"1900"`, `"c. 1900"`, 1),
+			[]string{`gutenberg-museum.json:3: last_updated: "c. 1900" does not match ^[0-9]{4}$`}},
+		{"whitespace", "gutenberg-museum.json", museum(`"address": "Liebfrauenplatz 5 "`),
+			[]string{"gutenberg-museum.json:2: warning: address: leading or trailing whitespace (fixable)"}},
+
+		// Languages.
+		{"language tag", "gutenberg-museum.json", museum(`"names": {"xx": "Museum"}`),
+			[]string{`gutenberg-museum.json:2: names/xx: unknown language in tag "xx"; add it to museums/languages.csv`}},
+		{"language code", "gutenberg-museum.json", museum(`"languages": ["qqq"]`),
+			[]string{`gutenberg-museum.json:2: languages/0: unknown ISO 639-3 code "qqq"; add it to museums/languages.csv`}},
+
+		// Country.
+		{"unknown country", "gutenberg-museum.json", museum(`"country": "ZZ"`),
+			[]string{`gutenberg-museum.json:2: country: "ZZ" is not an ISO 3166-1 alpha-2 code`}},
+		{"country and location", "gutenberg-museum.json", museum(`"country": "FR"`),
+			[]string{`gutenberg-museum.json:2: country: FR does not match location "Mainz, Germany" (DE)`}},
+
+		// URL.
+		{"several URLs", "gutenberg-museum.json", museum(`"url": "http://a.example.org http://b.example.org"`),
+			[]string{"gutenberg-museum.json:2: url: holds several values; keep one URL"}},
+		{"email as URL", "gutenberg-museum.json", museum(`"url": "info@example.org"`),
+			[]string{`gutenberg-museum.json:2: url: "info@example.org" looks like an email address`}},
+		{"bare URL", "gutenberg-museum.json", museum(`"url": "www.gutenberg-museum.de"`),
+			[]string{"gutenberg-museum.json:2: url: missing scheme; want http://www.gutenberg-museum.de (fixable)"}},
+		{"not a URL", "gutenberg-museum.json", museum(`"url": "gutenberg"`),
+			[]string{`gutenberg-museum.json:2: url: "gutenberg" is not a URL`}},
+		{"URL scheme", "gutenberg-museum.json", museum(`"url": "ftp://example.org"`),
+			[]string{`gutenberg-museum.json:2: url: scheme must be http or https, not "ftp"`}},
+		{"URL host", "gutenberg-museum.json", museum(`"url": "http://localhost/"`),
+			[]string{`gutenberg-museum.json:2: url: invalid host "localhost"`}},
+		{"empty URL", "gutenberg-museum.json", museum(`"url": ""`),
+			[]string{"gutenberg-museum.json:2: url: empty URL"}},
+		{"additional URLs", "gutenberg-museum.json", museum(`"additionalUrls": ["http://a.example.org", "a@example.org"]`),
+			[]string{`gutenberg-museum.json:2: additionalUrls/1: "a@example.org" looks like an email address`}},
+		{"additional addresses", "gutenberg-museum.json", museum(`"additionalEmails": ["a@example.org", "b"]`),
+			[]string{`gutenberg-museum.json:2: additionalEmails/1: "b" is not an email address`}},
+
+		// Email.
+		{"several addresses", "gutenberg-museum.json", museum(`"email": "a@example.org b@example.org"`),
+			[]string{"gutenberg-museum.json:2: email: holds several values; keep one address"}},
+		{"display name", "gutenberg-museum.json", museum(`"email": "Museum <a@example.org>"`),
+			[]string{"gutenberg-museum.json:2: email: holds several values; keep one address"}},
+		{"not an address", "gutenberg-museum.json", museum(`"email": "<a@example.org>"`),
+			[]string{`gutenberg-museum.json:2: email: "<a@example.org>" is not an email address`}},
+		{"address domain", "gutenberg-museum.json", museum(`"email": "a@localhost"`),
+			[]string{`gutenberg-museum.json:2: email: "a@localhost" has an invalid domain`}},
+
+		// Phone.
+		{"country code", "gutenberg-museum.json", museum(`"phone": "06131 122503"`),
+			[]string{`gutenberg-museum.json:2: phone: "06131 122503" must start with + and the country code`}},
+		{"alternatives", "gutenberg-museum.json", museum(`"phone": "+49 6131 122503 Phone 6131 122640"`),
+			[]string{`gutenberg-museum.json:2: phone: "+49 6131 122503 Phone 6131 122640" is not a phone number; separate alternatives with " or "`}},
+		{"phone spacing", "gutenberg-museum.json", museum(`"phone": "+ 49  6131 122503"`),
+			[]string{`gutenberg-museum.json:2: warning: phone: irregular spacing; want "+49 6131 122503" (fixable)`}},
+	}
+	l := New()
+	for _, tt := range tests {
+		if got := lines(l.File(tt.name, []byte(tt.data))); !slices.Equal(got, tt.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"gutenberg-museum.json":         {Data: []byte(museum(""))},
+		"gutenberg-museum-germany.json": {Data: []byte(museum(""))},
+		"gutenberg-museum-france.json":  {Data: []byte(strings.Replace(museum(""), "Mainz, Germany", "Paris, France", 1))},
+		"notes.txt":                     {Data: []byte("not a museum")},
+	}
+	diags, err := New().FS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"gutenberg-museum-germany.json:1: name: same museum name and country as gutenberg-museum.json",
+		"gutenberg-museum.json:1: name: same museum name and country as gutenberg-museum-germany.json",
+	}
+	if got := lines(diags); !slices.Equal(got, want) {
+		t.Errorf("FS:\ngot  %q\nwant %q", got, want)
+	}
+	if !HasErrors(diags) || HasErrors(nil) {
+		t.Errorf("HasErrors is wrong")
+	}
+}
+
+func TestDataset(t *testing.T) {
+	for _, dir := range []string{"../../../../museums-json", "../data"} {
+		diags, err := New().Dir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range diags {
+			if d.Severity == SeverityError {
+				t.Error(d)
+			}
+		}
+	}
+}
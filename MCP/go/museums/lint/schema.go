@@ -0,0 +1,291 @@
+package lint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wordnik/mcp-server/museums/jsondoc"
+)
+
+// SchemaJSON is the JSON Schema every file of museums-json must satisfy.
+//
+//go:embed museum.schema.json
+var SchemaJSON []byte
+
+// Schema is the subset of JSON Schema (draft 2020-12) the dataset uses.
+// Keywords outside it, such as format, are annotations only; the checks in
+// this package cover them.
+type Schema struct {
+	Type                 typeList            `json:"type"`
+	Required             []string            `json:"required"`
+	DependentRequired    map[string][]string `json:"dependentRequired"`
+	Properties           map[string]*Schema  `json:"properties"`
+	AdditionalProperties *Schema             `json:"additionalProperties"`
+	PropertyNames        *Schema             `json:"propertyNames"`
+	Items                *Schema             `json:"items"`
+	Enum                 []any               `json:"enum"`
+	Const                json.RawMessage     `json:"const"`
+	Pattern              string              `json:"pattern"`
+	MinLength            *int                `json:"minLength"`
+	MaxLength            *int                `json:"maxLength"`
+	Minimum              *float64            `json:"minimum"`
+	Maximum              *float64            `json:"maximum"`
+	MinItems             *int                `json:"minItems"`
+	UniqueItems          bool                `json:"uniqueItems"`
+	If                   *Schema             `json:"if"`
+	Then                 *Schema             `json:"then"`
+	Else                 *Schema             `json:"else"`
+
+	// forbidden is set for the boolean schema false.
+	forbidden bool
+	pattern   *regexp.Regexp
+}
+
+// typeList accepts both "type": "string" and "type": ["string", "null"].
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// UnmarshalJSON accepts the boolean schemas true and false besides objects.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*s = Schema{forbidden: !b}
+		return nil
+	}
+	type plain Schema
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	return nil
+}
+
+// ParseSchema decodes a schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return &s, nil
+}
+
+// DefaultSchema returns the parsed SchemaJSON. It panics if the embedded
+// schema is invalid.
+func DefaultSchema() *Schema {
+	s, err := ParseSchema(SchemaJSON)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Violation is a value that does not satisfy the schema. Pointer is the
+// JSON pointer of the value, e.g. "/url".
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+// Validate checks a decoded JSON value (as produced by encoding/json with
+// UseNumber) against s.
+func (s *Schema) Validate(v any) []Violation {
+	var out []Violation
+	s.validate(v, "", &out)
+	return out
+}
+
+func (s *Schema) validate(v any, ptr string, out *[]Violation) {
+	report := func(format string, args ...any) {
+		*out = append(*out, Violation{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.forbidden {
+		report("not allowed")
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		report("must be %s, not %s", strings.Join(s.Type, " or "), typeOf(v))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		report("must be one of %s", enumList(s.Enum))
+	}
+	if s.Const != nil && !inEnum(v, []any{s.Const}) {
+		report("must be %s", s.Const)
+	}
+	if s.If != nil {
+		branch := s.Else
+		if len(s.If.Validate(v)) == 0 {
+			branch = s.Then
+		}
+		if branch != nil {
+			branch.validate(v, ptr, out)
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			if *s.MinLength == 1 {
+				report("must not be empty")
+			} else {
+				report("must be at least %d characters", *s.MinLength)
+			}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			report("must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			report("%q does not match %s", v, s.Pattern)
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			report("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			report("must be at most %v", *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			report("must have at least %d items", *s.MinItems)
+		}
+		seen := map[string]bool{}
+		for i, item := range v {
+			if s.UniqueItems {
+				key, _ := json.Marshal(item)
+				if seen[string(key)] {
+					report("item %d is a duplicate", i)
+				}
+				seen[string(key)] = true
+			}
+			if s.Items != nil {
+				s.Items.validate(item, fmt.Sprintf("%s/%d", ptr, i), out)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required key %q", name)
+			}
+		}
+		for _, name := range sortedKeys(s.DependentRequired) {
+			if _, ok := v[name]; !ok {
+				continue
+			}
+			for _, dep := range s.DependentRequired[name] {
+				if _, ok := v[dep]; !ok {
+					report("key %q requires %q", name, dep)
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := ptr + "/" + jsondoc.EscapePointer(k)
+			if s.PropertyNames != nil {
+				s.PropertyNames.validate(k, child, out)
+			}
+			if prop, ok := s.Properties[k]; ok {
+				prop.validate(v[k], child, out)
+			} else if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.forbidden {
+					*out = append(*out, Violation{Pointer: child, Message: "unknown key"})
+				} else {
+					s.AdditionalProperties.validate(v[k], child, out)
+				}
+			}
+		}
+	}
+}
+
+func (t typeList) matches(v any) bool {
+	for _, name := range t {
+		switch name {
+		case "integer":
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+					return true
+				}
+			}
+		case typeOf(v):
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(v any, enum []any) bool {
+	got, _ := json.Marshal(v)
+	for _, e := range enum {
+		want, _ := json.Marshal(e)
+		if string(got) == string(want) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["id"],
+  "additionalProperties": false,
+  "dependentRequired": {"lat": ["lon"]},
+  "properties": {
+    "id": {"type": "integer", "minimum": 1, "maximum": 9},
+    "name": {"type": "string", "minLength": 1, "maxLength": 5},
+    "code": {"type": "string", "pattern": "^[A-Z]{2}$"},
+    "kind": {"enum": ["a", "b"]},
+    "version": {"const": 2},
+    "note": {"type": ["string", "null"]},
+    "tags": {"type": "array", "minItems": 1, "uniqueItems": true, "items": {"type": "string", "minLength": 2}},
+    "names": {"type": "object", "propertyNames": {"pattern": "^[a-z]+$"}, "additionalProperties": {"type": "string"}},
+    "lat": {"type": "number"},
+    "lon": {"type": "number"},
+    "legacy": false
+  },
+  "if": {"required": ["kind"], "properties": {"kind": {"const": "a"}}},
+  "then": {"required": ["name"]},
+  "else": {"required": ["code"]}
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		doc  string
+		want []string
+	}{
+		{`{"id": 1, "kind": "a", "name": "x"}`, nil},
+		{`{"id": 1, "code": "DE", "note": null, "tags": ["ab", "cd"], "names": {"en": "x"}, "lat": 1, "lon": 2}`, nil},
+
+		// Types.
+		{`[]`, []string{": must be object, not array"}},
+		{`{"id": 1.5, "code": "DE"}`, []string{"/id: must be integer, not number"}},
+		{`{"id": "1", "code": "DE"}`, []string{"/id: must be integer, not string"}},
+		{`{"id": 1, "code": "DE", "note": 3}`, []string{"/note: must be string or null, not number"}},
+
+		// Values.
+		{`{"id": 0, "code": "DE"}`, []string{"/id: must be at least 1"}},
+		{`{"id": 10, "code": "DE"}`, []string{"/id: must be at most 9"}},
+		{`{"id": 1, "kind": "a", "name": ""}`, []string{"/name: must not be empty"}},
+		{`{"id": 1, "kind": "a", "name": "longer"}`, []string{"/name: must be at most 5 characters"}},
+		{`{"id": 1, "code": "de"}`, []string{`/code: "de" does not match ^[A-Z]{2}$`}},
+		{`{"id": 1, "code": "DE", "kind": "c"}`, []string{`/kind: must be one of "a", "b"`}},
+		{`{"id": 1, "code": "DE", "version": 3}`, []string{"/version: must be 2"}},
+
+		// Arrays.
+		{`{"id": 1, "code": "DE", "tags": []}`, []string{"/tags: must have at least 1 items"}},
+		{`{"id": 1, "code": "DE", "tags": ["ab", "ab"]}`, []string{"/tags: item 1 is a duplicate"}},
+		{`{"id": 1, "code": "DE", "tags": ["ab", "c"]}`, []string{"/tags/1: must be at least 2 characters"}},
+
+		// Objects.
+		{`{"code": "DE"}`, []string{`: missing required key "id"`}},
+		{`{"id": 1, "code": "DE", "lat": 1}`, []string{`: key "lat" requires "lon"`}},
+		{`{"id": 1, "code": "DE", "extra": 1}`, []string{"/extra: unknown key"}},
+		{`{"id": 1, "code": "DE", "legacy": 1}`, []string{"/legacy: not allowed"}},
+		{`{"id": 1, "code": "DE", "names": {"EN": "x", "a/b": 1}}`, []string{
+			`/names/EN: "EN" does not match ^[a-z]+$`,
+			`/names/a~1b: "a/b" does not match ^[a-z]+$`,
+			"/names/a~1b: must be string, not number",
+		}},
+
+		// Conditions.
+		{`{"id": 1, "kind": "a"}`, []string{`: missing required key "name"`}},
+		{`{"id": 1, "kind": "b"}`, []string{`: missing required key "code"`}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, v := range s.Validate(decode(t, tt.doc)) {
+			got = append(got, v.Pointer+": "+v.Message)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	if _, err := ParseSchema([]byte(`{"pattern": "["}`)); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+	if _, err := ParseSchema([]byte(`{"type": 1}`)); err == nil {
+		t.Error("invalid type accepted")
+	}
+	DefaultSchema() // panics if the embedded schema is invalid
+}
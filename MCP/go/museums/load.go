@@ -0,0 +1,86 @@
+package museums
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// The data directory is a copy of the repository's museums-json directory,
+// which lies outside this module and so cannot be embedded directly.
+//go:generate sh -c "rm -f data/*.json && cp ../../../museums-json/*.json data/"
+
+//go:embed data/*.json
+var embedded embed.FS
+
+// Embedded loads the copy of the dataset compiled into the binary.
+func Embedded() (*Index, error) {
+	sub, err := fs.Sub(embedded, "data")
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+// LoadDir loads the dataset from a directory such as museums-json.
+func LoadDir(dir string) (*Index, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load reads every *.json file at the root of fsys.
+func Load(fsys fs.FS) (*Index, error) {
+	list, err := ReadAll(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(list), nil
+}
+
+// ReadAll decodes every *.json file at the root of fsys without indexing
+// them, so that callers can inspect duplicates and file names.
+func ReadAll(fsys fs.FS) ([]Museum, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var list []Museum
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		m, err := ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// ReadFile decodes one museum file and derives its slug from the file name.
+func ReadFile(fsys fs.FS, name string) (Museum, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Museum{}, err
+	}
+	return Decode(name, data)
+}
+
+// Decode decodes the contents of the museum file name, for callers that
+// have already read it.
+func Decode(name string, data []byte) (Museum, error) {
+	var m Museum
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Museum{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		return Museum{}, fmt.Errorf("%s: missing name", name)
+	}
+	m.Slug = Slugify(name)
+	m.File = name
+	return m, nil
+}
@@ -0,0 +1,141 @@
+// Package museums loads and queries the language museums dataset, one JSON
+// file per museum in the repository's museums-json directory.
+package museums
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Museum is one entry of the dataset.
+type Museum struct {
+	// Slug identifies the museum and is derived from its file name, e.g.
+	// "alutiiq-museum" for alutiiq-museum.json.
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// Names holds the museum's names keyed by BCP 47 language tag, such as
+	// "ga" or "zh-Hans", including the name in the language it documents.
+	Names map[string]string `json:"names,omitempty"`
+	// LastUpdated holds the year the museum was founded, despite its name.
+	LastUpdated string `json:"last_updated,omitempty"`
+	// Category is the section of Museums_List.md the museum is listed
+	// under, one of the IDs in Categories.
+	Category string `json:"category,omitempty"`
+	// Languages lists the ISO 639-3 codes of the languages the museum is
+	// about. Museums of many languages or of writing in general have none.
+	Languages []string `json:"languages,omitempty"`
+	Location  string   `json:"location,omitempty"`
+	// Locality, Region, CountryCode, Lat and Lon structure Location. They
+	// are filled from it by cmd/museumgeo; CountryCode is ISO 3166-1
+	// alpha-2 and the coordinates are those of the town.
+	Locality    string   `json:"city,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	CountryCode string   `json:"country,omitempty"`
+	Lat         *float64 `json:"lat,omitempty"`
+	Lon         *float64 `json:"lon,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	// AdditionalURLs and AdditionalEmails hold further websites and
+	// addresses of museums with several, such as one per seat.
+	AdditionalURLs   []string `json:"additionalUrls,omitempty"`
+	Email            string   `json:"email,omitempty"`
+	AdditionalEmails []string `json:"additionalEmails,omitempty"`
+	Phone            string   `json:"phone,omitempty"`
+	Address          string   `json:"address,omitempty"`
+
+	// File is the name of the file the entry was loaded from.
+	File string `json:"-"`
+}
+
+// Category is a section of Museums_List.md.
+type Category struct {
+	ID      string `json:"id"`
+	Heading string `json:"heading"`
+}
+
+// Categories lists the sections of Museums_List.md in the order they appear.
+var Categories = []Category{
+	{ID: "languages-of-the-world", Heading: "Museums of language and languages of the world"},
+	{ID: "one-language", Heading: "Museums of one language or group of languages"},
+	{ID: "writing", Heading: "Museums of writing and written culture"},
+	{ID: "persons", Heading: "Museums in memory of persons"},
+	{ID: "planned", Heading: "Plans and projects for new museums"},
+}
+
+// Planned is the category of museums that have not opened yet. They are the
+// only entries without a founding year.
+const Planned = "planned"
+
+var yearPattern = regexp.MustCompile(`\b\d{4}\b`)
+
+// FoundingYear returns the year recorded in LastUpdated, or 0 if there is none.
+func (m Museum) FoundingYear() int {
+	y, err := strconv.Atoi(yearPattern.FindString(m.LastUpdated))
+	if err != nil {
+		return 0
+	}
+	return y
+}
+
+// City returns Locality if set, or else the first component of Location
+// ("Kodiak" for "Kodiak, Alaska, USA"), or "" if the location has a single
+// component.
+func (m Museum) City() string {
+	if m.Locality != "" {
+		return m.Locality
+	}
+	parts := locationParts(m.Location)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// Country returns the last component of Location as written in the
+// dataset ("USA" for "Kodiak, Alaska, USA").
+func (m Museum) Country() string {
+	parts := locationParts(m.Location)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+func locationParts(location string) []string {
+	var parts []string
+	for _, p := range strings.Split(location, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+var (
+	nonSlug     = regexp.MustCompile(`[^a-z0-9]+`)
+	apostrophes = strings.NewReplacer("'", "", "’", "")
+)
+
+// Slugify turns a file name or museum name into a slug: lowercase ASCII
+// letters and digits separated by single hyphens. Apostrophes are dropped
+// rather than split on, so "Dr. Johnson's House" becomes dr-johnsons-house,
+// as in the file names the dataset was generated with.
+func Slugify(s string) string {
+	s = apostrophes.Replace(strings.TrimSuffix(s, ".json"))
+	return strings.Trim(nonSlug.ReplaceAllString(Fold(s), "-"), "-")
+}
+
+// Detail is a museum with its derived fields spelled out, as served to
+// clients. Its country key keeps the ISO code it has in the dataset files;
+// CountryName is the country as written in Location.
+type Detail struct {
+	Museum
+	City         string `json:"city,omitempty"`
+	CountryName  string `json:"countryName,omitempty"`
+	FoundingYear int    `json:"foundingYear,omitempty"`
+}
+
+// Detail returns m with its derived fields.
+func (m Museum) Detail() Detail {
+	return Detail{Museum: m, City: m.City(), CountryName: m.Country(), FoundingYear: m.FoundingYear()}
+}
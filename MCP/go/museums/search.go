@@ -0,0 +1,106 @@
+package museums
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/wordnik/mcp-server/internal/levenshtein"
+)
+
+// Match is a search hit with its relevance score; higher is better.
+type Match struct {
+	Museum Museum
+	Score  float64
+}
+
+// Search ranks the museums matching q by how well text matches their names,
+// languages, location and address. Name matches tolerate typos: each word of text
+// matches the closest word of the name within an edit distance of about a
+// third of its length. An empty text returns every museum matching q.
+func (ix *Index) Search(text string, q Query) []Match {
+	terms := tokens(text)
+	var out []Match
+	for _, m := range ix.museums {
+		if !q.matches(m) {
+			continue
+		}
+		score := 1.0
+		if len(terms) > 0 {
+			score = matchScore(terms, m)
+			if score == 0 {
+				continue
+			}
+		}
+		out = append(out, Match{Museum: m, Score: score})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// Suggest returns up to n slugs closest to slug, for "did you mean" hints.
+func (ix *Index) Suggest(slug string, n int) []string {
+	type candidate struct {
+		slug string
+		dist int
+	}
+	slug = Slugify(slug)
+	var cands []candidate
+	for _, m := range ix.museums {
+		d := levenshtein.Distance(slug, m.Slug)
+		if strings.Contains(m.Slug, slug) {
+			d = 0
+		}
+		if d <= len(slug)/2 {
+			cands = append(cands, candidate{m.Slug, d})
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].dist < cands[j].dist })
+	var out []string
+	for i := 0; i < len(cands) && i < n; i++ {
+		out = append(out, cands[i].slug)
+	}
+	return out
+}
+
+// matchScore averages the best score of each term: 1 for a word of any of
+// the names with that prefix, less for fuzzy name matches, 0.5 for a
+// substring of the location, address or a language name and 0 for no match. Any unmatched term rejects the museum.
+func matchScore(terms []string, m Museum) float64 {
+	name := tokens(m.Name)
+	for _, n := range m.Names {
+		name = append(name, tokens(n)...)
+	}
+	other := Fold(m.Location + " " + m.Address)
+	for _, l := range m.LanguageList() {
+		other += " " + Fold(l.Name)
+	}
+	var total float64
+	for _, t := range terms {
+		best := 0.0
+		for _, w := range name {
+			switch {
+			case strings.HasPrefix(w, t):
+				best = 1
+			case len([]rune(t)) >= 4:
+				if d := levenshtein.Distance(t, w); d <= len([]rune(t))/3 {
+					best = max(best, 1-float64(d)/float64(len([]rune(t))))
+				}
+			}
+		}
+		if best == 0 && strings.Contains(other, t) {
+			best = 0.5
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total / float64(len(terms))
+}
+
+func tokens(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,48 @@
+package museums
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds the current Index and lets it be replaced while readers are
+// using it.
+type Store struct {
+	current atomic.Pointer[Index]
+}
+
+// NewStore returns a Store serving ix.
+func NewStore(ix *Index) *Store {
+	s := &Store{}
+	s.current.Store(ix)
+	return s
+}
+
+// Index returns the index currently served.
+func (s *Store) Index() *Index {
+	return s.current.Load()
+}
+
+// Swap replaces the index served.
+func (s *Store) Swap(ix *Index) {
+	s.current.Store(ix)
+}
+
+var (
+	defaultStore     *Store
+	defaultStoreOnce sync.Once
+)
+
+// DefaultStore returns the process-wide store, initially serving the
+// embedded dataset. It panics if the embedded copy cannot be decoded, which
+// can only happen if a malformed file was compiled in.
+func DefaultStore() *Store {
+	defaultStoreOnce.Do(func() {
+		ix, err := Embedded()
+		if err != nil {
+			panic("museums: embedded dataset: " + err.Error())
+		}
+		defaultStore = NewStore(ix)
+	})
+	return defaultStore
+}
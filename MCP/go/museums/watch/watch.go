@@ -0,0 +1,213 @@
+// Package watch reloads the museums dataset from a directory while the
+// server runs. It polls the directory rather than relying on file system
+// notifications, so it behaves the same on every platform and on network
+// and container mounts, and it only swaps in data that passes the schema.
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/museums/jsondoc"
+	"github.com/wordnik/mcp-server/museums/lint"
+)
+
+// DefaultInterval is how often the directory is polled when Interval is
+// unset.
+const DefaultInterval = 2 * time.Second
+
+// maxReported bounds the schema violations quoted in a ValidationError.
+const maxReported = 5
+
+// Watcher swaps the index of Store whenever the *.json files in Dir change.
+type Watcher struct {
+	Dir   string
+	Store *museums.Store
+	// Interval is the polling period. A change is loaded once the files
+	// have stayed the same for one period, so that a merge or copy in
+	// progress is not loaded half-done.
+	Interval time.Duration
+	// Schema validates each file before a swap; nil means the schema in
+	// museums/lint.
+	Schema *lint.Schema
+	// OnSwap is called after a new index has been swapped in, for example
+	// to register the museum resources again.
+	OnSwap func(ix *museums.Index)
+	// OnError is called when a reload fails; the old index stays in use.
+	OnError func(err error)
+}
+
+// ValidationError reports files that failed validation. The data was not
+// loaded.
+type ValidationError struct {
+	Diagnostics []lint.Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d schema violations", len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		if i == maxReported {
+			fmt.Fprintf(&b, "; ...")
+			break
+		}
+		fmt.Fprintf(&b, "; %s", d)
+	}
+	return b.String()
+}
+
+// Reload reads and validates the directory and, if every file is valid,
+// swaps the new index into the store. Each file is read once, so the data
+// loaded is the data that was validated even if a file changes meanwhile.
+// On error the store is unchanged.
+func (w *Watcher) Reload() (*museums.Index, error) {
+	list, err := w.read(os.DirFS(w.Dir))
+	if err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", w.Dir, err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%s: no museum files", w.Dir)
+	}
+	ix := museums.NewIndex(list)
+	w.Store.Swap(ix)
+	if w.OnSwap != nil {
+		w.OnSwap(ix)
+	}
+	return ix, nil
+}
+
+// read decodes every *.json file after checking that it is a JSON object
+// that satisfies the schema. The other lint checks, such as phone number
+// formats, only warn contributors and do not stop a reload.
+func (w *Watcher) read(fsys fs.FS) ([]museums.Museum, error) {
+	schema := w.Schema
+	if schema == nil {
+		schema = lint.DefaultSchema()
+	}
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	var (
+		list  []museums.Museum
+		diags []lint.Diagnostic
+	)
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		doc, line, err := jsondoc.Parse(data)
+		if err != nil {
+			diags = append(diags, lint.Diagnostic{File: name, Line: line, Severity: lint.SeverityError, Message: "invalid JSON: " + err.Error()})
+			continue
+		}
+		for _, v := range schema.Validate(doc.Value()) {
+			diags = append(diags, lint.Diagnostic{File: name, Line: doc.Line(v.Pointer), Field: v.Pointer, Severity: lint.SeverityError, Message: v.Message})
+		}
+		if len(diags) > 0 {
+			// Keep validating to report every file, but load nothing.
+			continue
+		}
+		m, err := museums.Decode(name, data)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	if len(diags) > 0 {
+		return nil, &ValidationError{Diagnostics: diags}
+	}
+	return list, nil
+}
+
+// Run polls the directory until ctx is done, reloading it after each
+// change, and returns ctx.Err(). It does not load the directory first; call
+// Reload for that. A directory that cannot be read is reported once and
+// polled until it can.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	loaded, err := fingerprint(w.Dir)
+	lastErr := ""
+	if err != nil {
+		w.fail(err)
+		lastErr = err.Error()
+	}
+	pending := loaded
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current, err := fingerprint(w.Dir)
+		if err != nil {
+			if err.Error() != lastErr {
+				w.fail(err)
+				lastErr = err.Error()
+			}
+			continue
+		}
+		lastErr = ""
+		if current != pending {
+			// Changed since the last poll: wait until it settles.
+			pending = current
+			continue
+		}
+		if current == loaded {
+			continue
+		}
+		// A failed reload is not retried until the files change again.
+		loaded = current
+		if _, err := w.Reload(); err != nil {
+			w.fail(err)
+		}
+	}
+}
+
+func (w *Watcher) fail(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// fingerprint summarizes the names, sizes and modification times of the
+// *.json files in dir.
+func fingerprint(dir string) ([sha256.Size]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	var lines []string
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// Removed since the directory was read; the next poll sees it.
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s\x00%d\x00%d", e.Name(), info.Size(), info.ModTime().UnixNano()))
+	}
+	sort.Strings(lines)
+	return sha256.Sum256([]byte(strings.Join(lines, "\n"))), nil
+}
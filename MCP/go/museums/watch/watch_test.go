@@ -0,0 +1,162 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wordnik/mcp-server/museums"
+)
+
+const interval = 10 * time.Millisecond
+
+func museum(name string) string {
+	return `{
+  "name": "` + name + `",
+  "last_updated": "1900",
+  "category": "writing",
+  "location": "Mainz, Germany"
+}`
+}
+
+func write(t *testing.T, dir, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// names lists the museum names served by store.
+func names(store *museums.Store) []string {
+	var out []string
+	for _, m := range store.Index().All() {
+		out = append(out, m.Name)
+	}
+	return out
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	store := museums.NewStore(museums.NewIndex(nil))
+	w := &Watcher{Dir: dir, Store: store}
+
+	if _, err := w.Reload(); err == nil {
+		t.Fatal("empty directory loaded")
+	}
+	if store.Index().Len() != 0 {
+		t.Fatal("empty directory swapped the index")
+	}
+
+	write(t, dir, "gutenberg-museum.json", museum("Gutenberg Museum"))
+	ix, err := w.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Index() != ix || ix.Len() != 1 {
+		t.Fatalf("index not swapped: %v", names(store))
+	}
+
+	tests := map[string]string{
+		"invalid JSON": "{",
+		"schema":       `{"name": "Grimmwelt"}`,
+	}
+	for desc, data := range tests {
+		write(t, dir, "grimmwelt.json", data)
+		_, err := w.Reload()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: Reload = %v, want a ValidationError", desc, err)
+		}
+		if store.Index() != ix {
+			t.Errorf("%s: index swapped: %v", desc, names(store))
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "gutenberg-museum.json", museum("Gutenberg Museum"))
+	store := museums.NewStore(museums.NewIndex(nil))
+	swaps := make(chan *museums.Index, 10)
+	errs := make(chan error, 10)
+	w := &Watcher{
+		Dir:      dir,
+		Store:    store,
+		Interval: interval,
+		OnSwap:   func(ix *museums.Index) { swaps <- ix },
+		OnError:  func(err error) { errs <- err },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Errorf("Run = %v, want context.Canceled", err)
+		}
+	}()
+
+	// expect waits for one event and then checks that no other follows
+	// while the files stay the same.
+	expect := func(desc string, wantSwap bool) {
+		t.Helper()
+		select {
+		case <-swaps:
+			if !wantSwap {
+				t.Fatalf("%s: unexpected swap: %v", desc, names(store))
+			}
+		case err := <-errs:
+			if wantSwap {
+				t.Fatalf("%s: unexpected error: %v", desc, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: no reload", desc)
+		}
+		select {
+		case <-swaps:
+			t.Fatalf("%s: swapped twice", desc)
+		case err := <-errs:
+			t.Fatalf("%s: reported twice: %v", desc, err)
+		case <-time.After(10 * interval):
+		}
+	}
+
+	// Run does not load the files already present.
+	time.Sleep(5 * interval)
+	if store.Index().Len() != 0 {
+		t.Fatal("initial files loaded")
+	}
+
+	write(t, dir, "grimmwelt.json", museum("Grimmwelt"))
+	expect("valid change", true)
+	if got := names(store); len(got) != 2 {
+		t.Fatalf("after valid change: %v", got)
+	}
+	ix := store.Index()
+
+	write(t, dir, "grimmwelt.json", `{"name": "Grimmwelt", "category": "writing"}`)
+	expect("invalid change", false)
+	if store.Index() != ix {
+		t.Fatal("invalid change swapped the index")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "grimmwelt.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "gutenberg-museum.json")); err != nil {
+		t.Fatal(err)
+	}
+	expect("no files", false)
+	if store.Index() != ix {
+		t.Fatal("empty directory swapped the index")
+	}
+
+	write(t, dir, "klingspor-museum.json", museum("Klingspor Museum"))
+	expect("recovery", true)
+	if got := names(store); len(got) != 1 || got[0] != "Klingspor Museum" {
+		t.Fatalf("after recovery: %v", got)
+	}
+}
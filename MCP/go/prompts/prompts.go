@@ -0,0 +1,293 @@
+// Package prompts offers MCP prompt templates for common lexical
+// workflows. Each prompt asks the model to use some of the server's tools,
+// and its arguments have completions.
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wordnik/mcp-server/completion"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/resources"
+)
+
+// Prompt names.
+const (
+	ExplainWord     = "explain_word"
+	CompareSynonyms = "compare_synonyms"
+	VocabularyQuiz  = "vocabulary_quiz"
+	DescribeMuseum  = "describe_museum"
+)
+
+// audiences are suggested for the audience arguments.
+var audiences = []string{"child", "language learner", "general reader", "writer", "linguist"}
+
+// quizStyles are the styles a vocabulary quiz can take.
+var quizStyles = []string{"multiple-choice", "fill-in-the-blank", "matching"}
+
+// maxQuizWords caps the words of a quiz or a comparison.
+const maxQuizWords = 30
+
+// prompt is a prompt with the tools it uses and its argument completions.
+type prompt struct {
+	prompt      mcp.Prompt
+	tools       []string
+	completions map[string]completion.Func
+	handler     server.PromptHandlerFunc
+}
+
+// Register adds the prompts to s and their argument completions to c. A
+// prompt is only offered if served reports every tool it uses as served.
+func Register(s *server.MCPServer, c *completion.Completer, cfg *config.APIConfig, store *museums.Store, served func(tool string) bool) {
+	words := completion.Words(cfg)
+	all := []prompt{
+		{
+			prompt: mcp.NewPrompt(ExplainWord,
+				mcp.WithPromptDescription("Explain a word: its definitions, where it comes from and how it is used"),
+				mcp.WithArgument("word", mcp.RequiredArgument(), mcp.ArgumentDescription("The word to explain")),
+				mcp.WithArgument("audience", mcp.ArgumentDescription("Who the explanation is for, e.g. a child or a linguist")),
+				mcp.WithArgument("partOfSpeech", mcp.ArgumentDescription("Comma-separated parts of speech to explain, e.g. noun,verb")),
+				mcp.WithArgument("sourceDictionary", mcp.ArgumentDescription("Dictionary to take the definitions from: ahd-5, century, wiktionary, webster or wordnet")),
+			),
+			tools: []string{"define", "etymology", "examples"},
+			completions: map[string]completion.Func{
+				"word":             words,
+				"audience":         completion.Static(audiences...),
+				"partOfSpeech":     completion.EachItem(completion.Static(models.PartsOfSpeech...)),
+				"sourceDictionary": completion.Static(models.SourceDictionaries...),
+			},
+			handler: explainWord,
+		},
+		{
+			prompt: mcp.NewPrompt(CompareSynonyms,
+				mcp.WithPromptDescription("Compare near-synonyms: how their meanings, tone and usage differ"),
+				mcp.WithArgument("words", mcp.RequiredArgument(), mcp.ArgumentDescription("Comma-separated words to compare, or one word to compare with its synonyms")),
+				mcp.WithArgument("context", mcp.ArgumentDescription("A sentence or situation the words are meant for")),
+				mcp.WithArgument("relationshipTypes", mcp.ArgumentDescription("For one word, the comma-separated relationship types to compare it with; synonym by default")),
+			),
+			tools: []string{"define", "related_words", "examples"},
+			completions: map[string]completion.Func{
+				"words":             completion.EachItem(words),
+				"relationshipTypes": completion.EachItem(completion.Static(models.RelationshipTypes...)),
+			},
+			handler: compareSynonyms,
+		},
+		{
+			prompt: mcp.NewPrompt(VocabularyQuiz,
+				mcp.WithPromptDescription("Build a vocabulary quiz from a word list, with an answer key"),
+				mcp.WithArgument("words", mcp.RequiredArgument(), mcp.ArgumentDescription("Comma-separated words to quiz")),
+				mcp.WithArgument("style", mcp.ArgumentDescription("multiple-choice (default), fill-in-the-blank or matching")),
+				mcp.WithArgument("questions", mcp.ArgumentDescription("Number of questions; one per word by default")),
+				mcp.WithArgument("audience", mcp.ArgumentDescription("Who takes the quiz, e.g. a language learner")),
+			),
+			tools: []string{"define", "examples"},
+			completions: map[string]completion.Func{
+				"words":    completion.EachItem(words),
+				"style":    completion.Static(quizStyles...),
+				"audience": completion.Static(audiences...),
+			},
+			handler: vocabularyQuiz,
+		},
+		{
+			prompt: mcp.NewPrompt(DescribeMuseum,
+				mcp.WithPromptDescription("Describe a language museum: where it is, what it shows and the languages it is about"),
+				mcp.WithArgument("slug", mcp.RequiredArgument(), mcp.ArgumentDescription("The museum's slug, e.g. alutiiq-museum")),
+			),
+			tools:       []string{"get_museum", "get_museum_languages"},
+			completions: map[string]completion.Func{"slug": completion.MuseumSlugs(store, nil)},
+			handler:     describeMuseum(store),
+		},
+	}
+	for _, p := range all {
+		if !servesAll(served, p.tools) {
+			continue
+		}
+		s.AddPrompt(p.prompt, p.handler)
+		for _, arg := range p.prompt.Arguments {
+			c.Prompt(p.prompt.Name, arg.Name, p.completions[arg.Name])
+		}
+	}
+}
+
+func servesAll(served func(string) bool, tools []string) bool {
+	for _, t := range tools {
+		if !served(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func explainWord(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	word := strings.TrimSpace(request.Params.Arguments["word"])
+	if word == "" {
+		return nil, fmt.Errorf("missing required argument: word")
+	}
+	text := fmt.Sprintf("Explain the word %q%s.\n\n", word, forAudience(request.Params.Arguments["audience"])) +
+		"Use the Wordnik tools: define for its definitions, etymology for where it comes from and examples for how it is used. "
+	pos, err := enumList("partOfSpeech", request.Params.Arguments["partOfSpeech"], models.PartsOfSpeech)
+	if err != nil {
+		return nil, err
+	}
+	if len(pos) > 0 {
+		text += fmt.Sprintf("Only explain it as %s: call define with partOfSpeech=%s. ", strings.Join(pos, " or "), strings.Join(pos, ","))
+	}
+	if dict := strings.TrimSpace(request.Params.Arguments["sourceDictionary"]); dict != "" {
+		if !slices.Contains(models.SourceDictionaries, dict) {
+			return nil, fmt.Errorf("invalid sourceDictionary %q: use one of %s", dict, strings.Join(models.SourceDictionaries, ", "))
+		}
+		text += fmt.Sprintf("Take the definitions from %s: call define with sourceDictionaries=%s. ", dict, dict)
+	}
+	text += "Start with its main senses and parts of speech, then its origin, then two or three example sentences with their sources. " +
+		"Only state what the tools return, and say so if the word has no entry."
+	return result(fmt.Sprintf("Explain %q", word), text), nil
+}
+
+func compareSynonyms(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	words, err := wordList(request.Params.Arguments["words"])
+	if err != nil {
+		return nil, err
+	}
+	var text string
+	if len(words) == 1 {
+		types, err := enumList("relationshipTypes", request.Params.Arguments["relationshipTypes"], models.RelationshipTypes)
+		if err != nil {
+			return nil, err
+		}
+		related := "related words"
+		if len(types) == 0 {
+			types, related = []string{"synonym"}, "synonyms"
+		}
+		text = fmt.Sprintf("Compare the word %q with its closest %s.\n\n", words[0], related) +
+			fmt.Sprintf("Use related_words with relationshipTypes=%s to find them and keep the three to five most common. ", strings.Join(types, ","))
+	} else {
+		text = fmt.Sprintf("Compare the words %s.\n\n", quoteAll(words))
+	}
+	text += "Use define for the definitions of each word and examples for how each is used. " +
+		"Explain how they differ in meaning, tone, register and typical contexts, and when one fits where the others do not. " +
+		"End with a short table: word, core meaning, connotation, typical use."
+	if situation := strings.TrimSpace(request.Params.Arguments["context"]); situation != "" {
+		text += fmt.Sprintf("\n\nSay which word suits this context best, and why: %s", situation)
+	}
+	return result("Compare "+strings.Join(words, ", "), text), nil
+}
+
+func vocabularyQuiz(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	words, err := wordList(request.Params.Arguments["words"])
+	if err != nil {
+		return nil, err
+	}
+	style := strings.TrimSpace(request.Params.Arguments["style"])
+	if style == "" {
+		style = quizStyles[0]
+	}
+	if !slices.Contains(quizStyles, style) {
+		return nil, fmt.Errorf("invalid style %q: use one of %s", style, strings.Join(quizStyles, ", "))
+	}
+	questions := len(words)
+	if q := strings.TrimSpace(request.Params.Arguments["questions"]); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 1 || n > 100 {
+			return nil, fmt.Errorf("invalid questions %q: use a number from 1 to 100", q)
+		}
+		questions = n
+	}
+	text := fmt.Sprintf("Build a %s vocabulary quiz of %d questions%s on these words: %s.\n\n",
+		style, questions, forAudience(request.Params.Arguments["audience"]), quoteAll(words)) +
+		"Use define for the meaning of each word and examples for real sentences to draw the questions from. " +
+		"Base every question and answer on what the tools return, spread the questions over the words, and make the wrong options plausible. " +
+		"Put the answer key, with a one-line explanation per answer, after all the questions."
+	return result(fmt.Sprintf("Vocabulary quiz on %d words", len(words)), text), nil
+}
+
+func describeMuseum(store *museums.Store) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		slug := strings.TrimSpace(request.Params.Arguments["slug"])
+		if slug == "" {
+			return nil, fmt.Errorf("missing required argument: slug")
+		}
+		ix := store.Index()
+		m, ok := ix.Get(slug)
+		if !ok {
+			if suggestions := ix.Suggest(slug, 3); len(suggestions) > 0 {
+				return nil, fmt.Errorf("no museum with slug %q; did you mean %s?", slug, strings.Join(suggestions, ", "))
+			}
+			return nil, fmt.Errorf("no museum with slug %q", slug)
+		}
+		data, err := json.MarshalIndent(m.Detail(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		text := fmt.Sprintf("Describe the language museum %s, whose record is attached.\n\n", m.Name) +
+			"Say where it is, what it shows and who runs it, using only the record. " +
+			"Then use get_museum_languages with its slug to introduce the languages it is about, with their Wordnik definitions."
+		return mcp.NewGetPromptResult("Describe "+m.Name, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      resources.MuseumURI(m.Slug),
+				MIMEType: "application/json",
+				Text:     string(data),
+			})),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+}
+
+func result(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
+
+// wordList splits a comma-separated list of words, dropping blanks and
+// duplicates.
+func wordList(list string) ([]string, error) {
+	var words []string
+	for _, w := range strings.Split(list, ",") {
+		if w = strings.TrimSpace(w); w != "" && !slices.Contains(words, w) {
+			words = append(words, w)
+		}
+	}
+	switch {
+	case len(words) == 0:
+		return nil, fmt.Errorf("missing required argument: words")
+	case len(words) > maxQuizWords:
+		return nil, fmt.Errorf("too many words: %d, at most %d", len(words), maxQuizWords)
+	}
+	return words, nil
+}
+
+// enumList splits a comma-separated list of the values of an API enum,
+// such as parts of speech, and checks each.
+func enumList(name, list string, allowed []string) ([]string, error) {
+	values := strings.Fields(strings.ReplaceAll(list, ",", " "))
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			return nil, fmt.Errorf("invalid %s %q: use any of %s", name, v, strings.Join(allowed, ", "))
+		}
+	}
+	return values, nil
+}
+
+func forAudience(audience string) string {
+	if audience = strings.TrimSpace(audience); audience == "" {
+		return ""
+	}
+	return " for " + audience
+}
+
+func quoteAll(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = strconv.Quote(w)
+	}
+	return strings.Join(quoted, ", ")
+}
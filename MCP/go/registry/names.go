@@ -0,0 +1,160 @@
+package registry
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// naming is how a tool is presented: its canonical name, title and
+// description, and whether it reaches the Wordnik API.
+type naming struct {
+	name  string
+	title string
+	// description replaces the one the tools package gives, if set.
+	description string
+	// openWorld is set for tools that call the Wordnik API, as opposed to
+	// those that only read the museums dataset or the arguments.
+	openWorld bool
+}
+
+// toolNames holds the naming of every tool by the name its tools package
+// gives it. Most of those names are generated from the API paths, such as
+// get_word_json_word_relatedWords; they remain callable as deprecated
+// aliases of the canonical names.
+var toolNames = map[string]naming{
+	"get_word_json_word_definitions": {"define", "Define a word",
+		"Returns dictionary definitions of a word, each with its part of speech, source dictionary and attribution. " +
+			"Use limit to cap the number of senses, partOfSpeech to keep some parts of speech only and sourceDictionaries to choose dictionaries. " +
+			"If the word has no entry, the result lists spelling suggestions instead.", true},
+	"get_word_json_word_relatedWords": {"related_words", "Related words",
+		"Returns words related to a word, grouped by relationship type: synonyms, antonyms, hypernyms, rhymes, same-context words and others. " +
+			"relationshipTypes keeps some types only, and limitPerRelationshipType caps each group.", true},
+	"get_word_json_word_examples": {"examples", "Usage examples",
+		"Returns example sentences using a word, with their source and year. Use limit and skip to page through them.", true},
+	"get_word_json_word_topExample": {"top_example", "Top usage example",
+		"Returns the single best example sentence for a word.", true},
+	"get_word_json_word_etymologies": {"etymology", "Word etymology",
+		"Returns the etymologies of a word: where it comes from and how its form and meaning developed, as dictionary text.", true},
+	"get_word_json_word_pronunciations": {"pronunciations", "Text pronunciations",
+		"Returns text pronunciations of a word from several dictionaries. typeFormat selects a notation such as ahd-5, arpabet or IPA.", true},
+	"get_word_json_word_hyphenation": {"hyphenation", "Syllables and hyphenation",
+		"Returns the syllables of a word in order, marking the stressed ones.", true},
+	"get_word_json_word_audio": {"word_audio", "Pronunciation audio",
+		"Returns recordings of a word being pronounced: file URLs, duration and attribution. The file URLs expire after a while; " +
+			"download=content returns the files themselves as audio, and download=proxy links them through this server in HTTP mode.", true},
+	"get_word_json_word_phrases": {"phrases", "Phrases with a word",
+		"Returns common two-word phrases (bigrams) that contain a word, ranked by how strongly the words go together.", true},
+	"get_word_json_word_frequency": {"word_frequency", "Word frequency",
+		"Returns how often a word was used in each year of a range, from the Wordnik corpus. To compare several words, use compare_word_frequency.", true},
+	"get_word_json_word_scrabbleScore": {"scrabble_score", "Scrabble score",
+		"Returns the Scrabble score of a word, or no entry if it is not a valid Scrabble word.", true},
+	"get_words_json_search_query": {"search_words", "Search words",
+		"Searches the dictionary for words matching a query, such as a prefix or a pattern with wildcards, optionally as a regular expression. " +
+			"Filters narrow the results by part of speech, length and frequency.", true},
+	"get_words_json_reverseDictionary": {"reverse_dictionary", "Reverse dictionary",
+		"Finds words from a description of their meaning, such as \"fear of spiders\". Filters narrow the results by part of speech, length and frequency.", true},
+	"get_words_json_randomWord": {"random_word", "Random word",
+		"Returns one random word, optionally limited by part of speech, length and how common it is.", true},
+	"get_words_json_randomWords": {"random_words", "Random words",
+		"Returns a list of random words, optionally limited by part of speech, length and how common they are.", true},
+	"get_words_json_wordOfTheDay": {"word_of_the_day", "Word of the day",
+		"Returns Wordnik's word of the day, with its definitions, examples and note, for today or for the given date.", true},
+
+	"word_of_the_day_archive": {"word_of_the_day_archive", "Word of the day archive", "", true},
+	"compare_word_frequency":  {"compare_word_frequency", "Compare word frequency", "", true},
+	"scan_text":               {"scan_text", "Scan verse", "", true},
+	"resolve_word":            {"resolve_word", "Resolve a word", "", true},
+	"word_graph":              {"word_graph", "Word relationship graph", "", true},
+	"search_museums":          {"search_museums", "Search language museums", "", false},
+	"get_museum":              {"get_museum", "Language museum", "", false},
+	"list_museum_countries":   {"list_museum_countries", "Language museum countries", "", false},
+	"get_museum_languages":    {"get_museum_languages", "Languages of a museum", "", true},
+}
+
+// present gives t its canonical name, title, description and annotations.
+// Every tool only reads, so all are read-only and idempotent.
+func present(t models.Tool) models.Tool {
+	n, ok := toolNames[t.Definition.Name]
+	if !ok {
+		n = naming{name: t.Definition.Name, openWorld: true}
+	}
+	def := t.Definition
+	def.Name = n.name
+	if n.description != "" {
+		def.Description = n.description
+	}
+	def.Annotations = mcp.ToolAnnotation{
+		Title:           n.title,
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(n.openWorld),
+	}
+	return models.Tool{Definition: def, Handler: t.Handler}
+}
+
+// Aliases returns the deprecated names of the tool called name, sorted.
+func Aliases(name string) []string {
+	var aliases []string
+	for old, n := range toolNames {
+		if n.name == name && old != name {
+			aliases = append(aliases, old)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Canonical returns the canonical name of a deprecated tool name.
+func Canonical(alias string) (string, bool) {
+	n, ok := toolNames[alias]
+	if !ok || n.name == alias {
+		return "", false
+	}
+	return n.name, true
+}
+
+// AliasTools returns a tool for each deprecated name of tools, so that
+// clients using the old names keep working. They call the same handlers;
+// HideAliases keeps them out of tools/list.
+func AliasTools(tools []models.Tool) []models.Tool {
+	var aliases []models.Tool
+	for _, t := range tools {
+		for _, old := range Aliases(t.Definition.Name) {
+			def := t.Definition
+			def.Name = old
+			def.Description = "Deprecated: use " + t.Definition.Name + ". " + t.Definition.Description
+			aliases = append(aliases, models.Tool{Definition: def, Handler: aliasHandler(old, t)})
+		}
+	}
+	return aliases
+}
+
+// warned records the aliases already reported, so that each is logged once.
+var warned sync.Map
+
+func aliasHandler(old string, t models.Tool) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, seen := warned.LoadOrStore(old, true); !seen {
+			log.Printf("Tool %s is deprecated; use %s", old, t.Definition.Name)
+		}
+		return t.Handler(ctx, req)
+	}
+}
+
+// HideAliases removes the deprecated aliases from a tool list. It is a
+// server.ToolFilterFunc.
+func HideAliases(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+	listed := make([]mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		if _, alias := Canonical(t.Name); !alias {
+			listed = append(listed, t)
+		}
+	}
+	return listed
+}
@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+func TestToolNames(t *testing.T) {
+	tools := GetAll(&config.APIConfig{})
+	seen := map[string]bool{}
+	for _, tool := range tools {
+		def := tool.Definition
+		if seen[def.Name] {
+			t.Errorf("%s is served twice", def.Name)
+		}
+		seen[def.Name] = true
+		if _, alias := Canonical(def.Name); alias {
+			t.Errorf("%s is served under a deprecated name", def.Name)
+		}
+		if len(def.Name) > 32 || def.Name != stripUnsafe(def.Name) {
+			t.Errorf("%s is not a concise snake_case name", def.Name)
+		}
+		a := def.Annotations
+		if a.Title == "" {
+			t.Errorf("%s has no title", def.Name)
+		}
+		if a.ReadOnlyHint == nil || !*a.ReadOnlyHint || a.DestructiveHint == nil || *a.DestructiveHint ||
+			a.IdempotentHint == nil || !*a.IdempotentHint || a.OpenWorldHint == nil {
+			t.Errorf("%s annotations %+v", def.Name, a)
+		}
+	}
+	for old := range toolNames {
+		if canonical, ok := Canonical(old); ok && !seen[canonical] {
+			t.Errorf("%s is an alias of %s, which is not served", old, canonical)
+		}
+	}
+	if len(toolNames) != len(tools) {
+		t.Errorf("%d namings for %d tools", len(toolNames), len(tools))
+	}
+	if *toolByName(t, tools, "search_museums").Definition.Annotations.OpenWorldHint {
+		t.Errorf("search_museums reads local data but is open-world")
+	}
+	if d := toolByName(t, tools, "define").Definition; d.Description == "Return definitions for a word" {
+		t.Errorf("define kept the generated description")
+	}
+}
+
+// stripUnsafe keeps lowercase letters, digits and underscores.
+func stripUnsafe(s string) string {
+	out := []rune{}
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+func toolByName(t *testing.T, tools []models.Tool, name string) models.Tool {
+	t.Helper()
+	for _, tool := range tools {
+		if tool.Definition.Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("no tool %s", name)
+	return models.Tool{}
+}
+
+func TestAliasTools(t *testing.T) {
+	tools := GetAll(&config.APIConfig{})
+	aliases := AliasTools(tools)
+	if got := Aliases("define"); !slices.Equal(got, []string{"get_word_json_word_definitions"}) {
+		t.Errorf("Aliases(define) = %v", got)
+	}
+	if got := Aliases("scan_text"); got != nil {
+		t.Errorf("Aliases(scan_text) = %v", got)
+	}
+	if c, ok := Canonical("get_words_json_search_query"); !ok || c != "search_words" {
+		t.Errorf("Canonical(get_words_json_search_query) = %q, %v", c, ok)
+	}
+
+	var alias models.Tool
+	for _, a := range aliases {
+		if a.Definition.Name == "get_word_json_word_scrabbleScore" {
+			alias = a
+		}
+	}
+	if alias.Handler == nil {
+		t.Fatalf("no alias get_word_json_word_scrabbleScore among %d", len(aliases))
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{}
+	res, err := alias.Handler(context.Background(), req)
+	if err != nil || !res.IsError {
+		t.Errorf("alias without a word = %+v, %v; want the tool's bad_input result", res, err)
+	}
+
+	var all []mcp.Tool
+	for _, tool := range append(tools, aliases...) {
+		all = append(all, tool.Definition)
+	}
+	listed := HideAliases(context.Background(), all)
+	if len(listed) != len(tools) {
+		t.Errorf("HideAliases listed %d tools, want %d", len(listed), len(tools))
+	}
+}
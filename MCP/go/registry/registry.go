@@ -0,0 +1,76 @@
+// Package registry lists the tools of the Wordnik MCP server, so that the
+// server and the wordnik command offer the same ones, and selects the
+// tools and resources a deployment serves.
+package registry
+
+import (
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+	tools_museum "github.com/wordnik/mcp-server/tools/museum"
+	tools_word "github.com/wordnik/mcp-server/tools/word"
+	tools_words "github.com/wordnik/mcp-server/tools/words"
+)
+
+// Groups of tools and resources. A group is the first part of the
+// qualified name that selection patterns match, as in
+// "word.define".
+const (
+	// GroupWord holds the lookups of a single word.
+	GroupWord = "word"
+	// GroupWords holds random words, searches and the word of the day.
+	GroupWords = "words"
+	// GroupMuseum holds the language museums tools and every resource.
+	GroupMuseum = "museum"
+)
+
+// GetAll returns every tool, with handlers that call the API configured by
+// cfg.
+func GetAll(cfg *config.APIConfig) []models.Tool {
+	return Selected(cfg, nil)
+}
+
+// Selected returns the tools that sel allows, in registry order, under
+// their canonical names. A nil sel allows every tool. Patterns match the
+// deprecated names too.
+func Selected(cfg *config.APIConfig, sel *Selection) []models.Tool {
+	store := museums.DefaultStore()
+	all := []struct {
+		group string
+		tool  models.Tool
+	}{
+		{GroupWord, tools_word.CreateGetphrasesTool(cfg)},
+		{GroupWord, tools_word.CreateGetscrabblescoreTool(cfg)},
+		{GroupWord, tools_word.CreateGetdefinitionsTool(cfg)},
+		{GroupWord, tools_word.CreateGetwordfrequencyTool(cfg)},
+		{GroupWord, tools_word.CreateComparefrequencyTool(cfg)},
+		{GroupWord, tools_word.CreateGetaudioTool(cfg)},
+		{GroupWords, tools_words.CreateGetrandomwordsTool(cfg)},
+		{GroupWords, tools_words.CreateSearchwordsTool(cfg)},
+		{GroupWord, tools_word.CreateGetexamplesTool(cfg)},
+		{GroupWord, tools_word.CreateGethyphenationTool(cfg)},
+		{GroupWords, tools_words.CreateGetrandomwordTool(cfg)},
+		{GroupWord, tools_word.CreateGetetymologiesTool(cfg)},
+		{GroupWord, tools_word.CreateGetrelatedwordsTool(cfg)},
+		{GroupWord, tools_word.CreateWordgraphTool(cfg)},
+		{GroupWords, tools_words.CreateGetwordofthedayTool(cfg)},
+		{GroupWords, tools_words.CreateWordofthedayarchiveTool(cfg)},
+		{GroupWord, tools_word.CreateGettextpronunciationsTool(cfg)},
+		{GroupWord, tools_word.CreateScantextTool(cfg)},
+		{GroupWords, tools_words.CreateReversedictionaryTool(cfg)},
+		{GroupWord, tools_word.CreateGettopexampleTool(cfg)},
+		{GroupWord, tools_word.CreateResolvewordTool(cfg)},
+		{GroupMuseum, tools_museum.CreateSearchmuseumsTool(store)},
+		{GroupMuseum, tools_museum.CreateGetmuseumTool(store)},
+		{GroupMuseum, tools_museum.CreateListmuseumcountriesTool(store)},
+		{GroupMuseum, tools_museum.CreateGetmuseumlanguagesTool(cfg, store)},
+	}
+	tools := make([]models.Tool, 0, len(all))
+	for _, t := range all {
+		tool := present(t.tool)
+		if sel.Allows(t.group, append([]string{tool.Definition.Name}, Aliases(tool.Definition.Name)...)...) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Profiles are the named selections a pattern can refer to as "@name".
+var Profiles = map[string][]string{
+	// all serves everything, the default.
+	"all": {"*"},
+	// lexical serves the Wordnik lookups and no museum data.
+	"lexical": {GroupWord + ".*", GroupWords + ".*"},
+	// museums serves the museum tools and resources.
+	"museums": {GroupMuseum + ".*"},
+}
+
+// A Selection decides which tools and resources are served. It is built
+// from patterns, applied in order:
+//
+//   - "name" includes what it matches, "-name" excludes it.
+//   - A pattern matches the tool name, a deprecated name of the tool, or
+//     the resource URI, or any of them qualified as "group.name", so
+//     "word.*" matches every tool of GroupWord.
+//   - "*" matches any run of characters and "?" any one character.
+//   - "@profile" stands for the patterns of a profile, and "-@profile"
+//     excludes them.
+//
+// The last pattern that matches decides. Anything no pattern matches is
+// served if the first pattern is an exclusion, and not served otherwise,
+// so "-word_audio" serves everything but one tool, while "word.*" serves
+// the word tools only. A nil Selection serves everything.
+type Selection struct {
+	rules []rule
+}
+
+type rule struct {
+	exclude bool
+	re      *regexp.Regexp
+}
+
+// NewSelection parses selection patterns. It returns nil, which serves
+// everything, if there are none.
+func NewSelection(patterns []string) (*Selection, error) {
+	var rules []rule
+	for _, p := range patterns {
+		expanded, err := expand(p)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, expanded...)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return &Selection{rules: rules}, nil
+}
+
+func expand(pattern string) ([]rule, error) {
+	pattern = strings.TrimSpace(pattern)
+	exclude := strings.HasPrefix(pattern, "-")
+	pattern = strings.TrimPrefix(pattern, "-")
+	if pattern == "" {
+		return nil, nil
+	}
+	name, isProfile := strings.CutPrefix(pattern, "@")
+	if !isProfile {
+		return []rule{{exclude: exclude, re: compile(pattern)}}, nil
+	}
+	profile, ok := Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool profile %q: use one of %s", name, strings.Join(profileNames(), ", "))
+	}
+	var rules []rule
+	for _, p := range profile {
+		expanded, err := expand(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range expanded {
+			r.exclude = r.exclude != exclude
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// compile converts a glob pattern to an anchored regular expression.
+func compile(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Allows reports whether the tool or resource in group is served. names
+// are its name and any deprecated ones; a pattern matching any of them
+// applies.
+func (s *Selection) Allows(group string, names ...string) bool {
+	if s == nil {
+		return true
+	}
+	allowed := s.rules[0].exclude
+	for _, r := range s.rules {
+		for _, name := range names {
+			if r.re.MatchString(name) || r.re.MatchString(group+"."+name) {
+				allowed = !r.exclude
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+// AllowsResource reports whether the resource or resource template with
+// the given URI is served. Every resource is in GroupMuseum.
+func (s *Selection) AllowsResource(uri string) bool {
+	return s.Allows(GroupMuseum, uri)
+}
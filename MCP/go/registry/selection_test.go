@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wordnik/mcp-server/config"
+)
+
+func servedTools(sel *Selection) []string {
+	var names []string
+	for _, t := range Selected(&config.APIConfig{}, sel) {
+		names = append(names, t.Definition.Name)
+	}
+	return names
+}
+
+func TestSelection(t *testing.T) {
+	all := servedTools(nil)
+	tests := []struct {
+		patterns []string
+		count    int      // tools served, or -1 to skip the check
+		want     []string // tools and resource URIs served
+		not      []string // tools and resource URIs not served
+	}{
+		{nil, len(all), []string{"word_audio", "search_museums", "museums://index"}, nil},
+		{[]string{"word.*", "-word_audio"}, -1,
+			[]string{"define", "scan_text"},
+			[]string{"word_audio", "random_word", "search_museums", "museums://index", "museum://{slug}"}},
+		{[]string{"-word_audio"}, len(all) - 1,
+			[]string{"define", "search_museums", "museums://index"},
+			[]string{"word_audio"}},
+		// Deprecated names still select their tools.
+		{[]string{"-get_word_json_word_audio"}, len(all) - 1, []string{"define"}, []string{"word_audio"}},
+		{[]string{"words.get_words_json_*"}, 5, []string{"random_word", "search_words"}, []string{"word_of_the_day_archive"}},
+		{[]string{"@museums"}, 4,
+			[]string{"search_museums", "get_museum_languages", "museums://index", "museum://alutiiq-museum", "museum://{slug}"},
+			[]string{"define"}},
+		{[]string{"@lexical"}, len(all) - 4,
+			[]string{"define", "word_of_the_day"},
+			[]string{"search_museums", "museums://index"}},
+		{[]string{"@all", "-@museums"}, len(all) - 4, []string{"define"}, []string{"get_museum", "museums://index"}},
+		{[]string{"-museum://*"}, len(all), []string{"search_museums", "museums://index"}, []string{"museum://alutiiq-museum", "museum://{slug}"}},
+		{[]string{"*museum*", "-museum.museum*"}, 4, []string{"get_museum"}, []string{"museums://index", "museum://{slug}"}},
+		{[]string{"word_?udio"}, 1, []string{"word_audio"}, nil},
+	}
+	for _, tt := range tests {
+		sel, err := NewSelection(tt.patterns)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.patterns, err)
+		}
+		tools := servedTools(sel)
+		if tt.count >= 0 && len(tools) != tt.count {
+			t.Errorf("%q serves %d tools, want %d: %v", tt.patterns, len(tools), tt.count, tools)
+		}
+		served := func(name string) bool {
+			if strings.Contains(name, "://") {
+				return sel.AllowsResource(name)
+			}
+			return slices.Contains(tools, name)
+		}
+		for _, name := range tt.want {
+			if !served(name) {
+				t.Errorf("%q does not serve %s", tt.patterns, name)
+			}
+		}
+		for _, name := range tt.not {
+			if served(name) {
+				t.Errorf("%q serves %s", tt.patterns, name)
+			}
+		}
+	}
+}
+
+func TestSelectionErrors(t *testing.T) {
+	if _, err := NewSelection([]string{"@nope"}); err == nil || !strings.Contains(err.Error(), `unknown tool profile "nope": use one of all, lexical, museums`) {
+		t.Errorf("unknown profile: %v", err)
+	}
+	if sel, err := NewSelection([]string{" ", "-"}); sel != nil || err != nil {
+		t.Errorf("empty patterns = %v, %v; want nil, nil", sel, err)
+	}
+}
@@ -0,0 +1,171 @@
+// Package resources exposes the language museums dataset as MCP resources.
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+const (
+	// MuseumScheme prefixes the URI of each museum: museum://{slug}.
+	MuseumScheme = "museum://"
+	// MuseumTemplate resolves museums that are not listed individually.
+	MuseumTemplate = MuseumScheme + "{slug}"
+	// IndexURI lists every museum.
+	IndexURI = "museums://index"
+	// CountryTemplate lists the museums of a country, by name or ISO code:
+	// museums://country/{country}.
+	CountryTemplate = CountryPrefix + "{country}"
+	// CountryPrefix prefixes the URI of each country listing.
+	CountryPrefix = "museums://country/"
+
+	mimeJSON = "application/json"
+)
+
+// indexEntry is one line of the museums://index resource.
+type indexEntry struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Location string `json:"location,omitempty"`
+	URI      string `json:"uri"`
+}
+
+// MuseumURI returns the resource URI of the museum with the given slug.
+func MuseumURI(slug string) string {
+	return MuseumScheme + slug
+}
+
+// RegisterMuseums adds the museum resources, the museum://{slug} template
+// and the index to s. Calling it again after the store has been swapped
+// replaces the resource list, which notifies clients with
+// resources/list_changed when the server declares that capability.
+//
+// Only the resources whose URI allow accepts are served: the index and the
+// templates, by IndexURI, MuseumTemplate and CountryTemplate, and each
+// museum by its URI, which the templates then refuse as well. A nil allow
+// serves everything.
+func RegisterMuseums(s *server.MCPServer, store *museums.Store, allow func(uri string) bool) {
+	if allow == nil {
+		allow = func(string) bool { return true }
+	}
+	ix := store.Index()
+	list := make([]server.ServerResource, 0, ix.Len()+1)
+	if allow(IndexURI) {
+		list = append(list, server.ServerResource{
+			Resource: mcp.NewResource(IndexURI, "Language museums index",
+				mcp.WithResourceDescription(fmt.Sprintf("All %d museums in the dataset with their slugs and resource URIs", ix.Len())),
+				mcp.WithMIMEType(mimeJSON),
+			),
+			Handler: indexHandler(store, allow),
+		})
+	}
+	for _, m := range ix.All() {
+		if !allow(MuseumURI(m.Slug)) {
+			continue
+		}
+		description := m.Name
+		if m.Location != "" {
+			description += ", " + m.Location
+		}
+		list = append(list, server.ServerResource{
+			Resource: mcp.NewResource(MuseumURI(m.Slug), m.Name,
+				mcp.WithResourceDescription(description),
+				mcp.WithMIMEType(mimeJSON),
+			),
+			Handler: museumHandler(store, allow),
+		})
+	}
+	s.SetResources(list...)
+	var templates []server.ServerResourceTemplate
+	if allow(MuseumTemplate) {
+		templates = append(templates, server.ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate(MuseumTemplate, "Language museum",
+				mcp.WithTemplateDescription("A language museum record by slug, e.g. museum://alutiiq-museum"),
+				mcp.WithTemplateMIMEType(mimeJSON),
+			),
+			Handler: museumTemplateHandler(store, allow),
+		})
+	}
+	if allow(CountryTemplate) {
+		templates = append(templates, server.ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate(CountryTemplate, "Language museums of a country",
+				mcp.WithTemplateDescription("The museums of a country, by name or ISO code, e.g. museums://country/Spain"),
+				mcp.WithTemplateMIMEType(mimeJSON),
+			),
+			Handler: countryHandler(store, allow),
+		})
+	}
+	s.SetResourceTemplates(templates...)
+}
+
+// museumHandler reads the museum at request time, so a reload is reflected
+// even for resources listed before it.
+func museumHandler(store *museums.Store, allow func(string) bool) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readMuseum(store, allow, request.Params.URI)
+	}
+}
+
+func museumTemplateHandler(store *museums.Store, allow func(string) bool) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readMuseum(store, allow, request.Params.URI)
+	}
+}
+
+func readMuseum(store *museums.Store, allow func(string) bool, uri string) ([]mcp.ResourceContents, error) {
+	slug := strings.TrimPrefix(uri, MuseumScheme)
+	m, ok := store.Index().Get(slug)
+	if !ok || !allow(MuseumURI(m.Slug)) {
+		return nil, fmt.Errorf("no museum with slug %q", slug)
+	}
+	return jsonContents(uri, m.Detail())
+}
+
+func indexHandler(store *museums.Store, allow func(string) bool) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return jsonContents(request.Params.URI, entries(store.Index().All(), allow))
+	}
+}
+
+func countryHandler(store *museums.Store, allow func(string) bool) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		country, err := url.PathUnescape(strings.TrimPrefix(request.Params.URI, CountryPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid country in %q", request.Params.URI)
+		}
+		list := entries(store.Index().ByCountry(country), allow)
+		if len(list) == 0 {
+			return nil, fmt.Errorf("no museums in country %q", country)
+		}
+		return jsonContents(request.Params.URI, list)
+	}
+}
+
+// entries returns the index entries of the museums that allow serves.
+func entries(all []museums.Museum, allow func(string) bool) []indexEntry {
+	list := make([]indexEntry, 0, len(all))
+	for _, m := range all {
+		if !allow(MuseumURI(m.Slug)) {
+			continue
+		}
+		list = append(list, indexEntry{Slug: m.Slug, Name: m.Name, Location: m.Location, URI: MuseumURI(m.Slug)})
+	}
+	return list
+}
+
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: mimeJSON, Text: string(data)},
+	}, nil
+}
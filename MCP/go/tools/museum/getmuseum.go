@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+func GetmuseumHandler(store *museums.Store) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		slug, ok := args["slug"].(string)
+		if !ok || slug == "" {
+			return client.ErrorResult(client.BadInput("missing required parameter: slug")), nil
+		}
+
+		ix := store.Index()
+		m, ok := ix.Get(slug)
+		if !ok {
+			msg := fmt.Sprintf("no museum with slug %q", slug)
+			if s := ix.Suggest(slug, 3); len(s) > 0 {
+				msg += "; did you mean " + strings.Join(s, ", ") + "?"
+			}
+			return client.ErrorResult(&client.Error{Code: client.CodeNotFound, Message: msg}), nil
+		}
+
+		result := m.Detail()
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+
+		return mcp.NewToolResultStructured(result, string(prettyJSON)), nil
+	}
+}
+
+func CreateGetmuseumTool(store *museums.Store) models.Tool {
+	tool := mcp.NewTool("get_museum",
+		mcp.WithDescription("Returns one language museum by its slug, the name of its file in museums-json without the extension"),
+		mcp.WithString("slug", mcp.Required(), mcp.Description("Museum slug, e.g. alutiiq-museum")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetmuseumHandler(store),
+	}
+}
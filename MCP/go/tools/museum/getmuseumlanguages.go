@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+// defaultLanguageDefinitions is the number of Wordnik definitions fetched
+// for each language name when definitionLimit is unset, and
+// maxLanguageDefinitions bounds it.
+const (
+	defaultLanguageDefinitions = 2
+	maxLanguageDefinitions     = 10
+)
+
+// languageDefinitionsTTL is how long definitions of language names are
+// cached; they rarely change.
+const languageDefinitionsTTL = 24 * time.Hour
+
+// wordnikWordURL is the Wordnik web page of a word.
+const wordnikWordURL = "https://www.wordnik.com/words/"
+
+// museumLanguage is one language of a museum, linked to Wordnik.
+type museumLanguage struct {
+	museums.Language
+	// Names are the museum's names in this language, keyed by BCP 47 tag.
+	Names map[string]string `json:"names,omitempty"`
+	// Word is the language name to pass to the Wordnik word tools, and
+	// WordnikURL its page on wordnik.com.
+	Word        string              `json:"word"`
+	WordnikURL  string              `json:"wordnikUrl"`
+	Definitions []models.Definition `json:"definitions,omitempty"`
+}
+
+type museumLanguages struct {
+	Slug      string           `json:"slug"`
+	Name      string           `json:"name"`
+	Languages []museumLanguage `json:"languages"`
+	// Failures are the languages whose definitions could not be fetched.
+	// They are still listed, without definitions.
+	Failures []definitionFailure `json:"failures,omitempty"`
+}
+
+// definitionFailure is a language name whose definitions could not be
+// fetched.
+type definitionFailure struct {
+	Word  string           `json:"word"`
+	Code  client.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+func GetmuseumlanguagesHandler(cfg *config.APIConfig, store *museums.Store) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		slug, ok := args["slug"].(string)
+		if !ok || slug == "" {
+			return client.ErrorResult(client.BadInput("missing required parameter: slug")), nil
+		}
+		limit := defaultLanguageDefinitions
+		if val, ok := args["definitionLimit"].(float64); ok {
+			limit = int(val)
+		}
+		if limit < 0 || limit > maxLanguageDefinitions {
+			return client.ErrorResult(client.BadInput("definitionLimit must be between 0 and %d, got %d", maxLanguageDefinitions, limit)), nil
+		}
+
+		ix := store.Index()
+		m, ok := ix.Get(slug)
+		if !ok {
+			msg := fmt.Sprintf("no museum with slug %q", slug)
+			if s := ix.Suggest(slug, 3); len(s) > 0 {
+				msg += "; did you mean " + strings.Join(s, ", ") + "?"
+			}
+			return client.ErrorResult(&client.Error{Code: client.CodeNotFound, Message: msg}), nil
+		}
+
+		result := museumLanguages{Slug: m.Slug, Name: m.Name, Languages: []museumLanguage{}}
+		for _, l := range m.LanguageList() {
+			word := l.Name
+			if word == "" {
+				word = l.Code
+			}
+			ml := museumLanguage{Language: l, Word: word, WordnikURL: wordnikWordURL + url.PathEscape(word)}
+			for tag, name := range m.Names {
+				if tl, ok := museums.TagLanguage(tag); ok && tl.Code == l.Code {
+					if ml.Names == nil {
+						ml.Names = map[string]string{}
+					}
+					ml.Names[tag] = name
+				}
+			}
+			result.Languages = append(result.Languages, ml)
+		}
+
+		if limit > 0 {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			errs := make([]error, len(result.Languages))
+			var wg sync.WaitGroup
+			for i := range result.Languages {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					l := &result.Languages[i]
+					errs[i] = client.GetCached(ctx, cfg, client.Path("word.json", l.Word, "definitions"), query, languageDefinitionsTTL, &l.Definitions)
+				}()
+			}
+			wg.Wait()
+			for i, err := range errs {
+				if err != nil && client.CodeOf(err) != client.CodeNotFound {
+					result.Failures = append(result.Failures, definitionFailure{Word: result.Languages[i].Word, Code: client.CodeOf(err), Error: err.Error()})
+				}
+			}
+		}
+
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+
+		return mcp.NewToolResultStructured(result, string(prettyJSON)), nil
+	}
+}
+
+func CreateGetmuseumlanguagesTool(cfg *config.APIConfig, store *museums.Store) models.Tool {
+	tool := mcp.NewTool("get_museum_languages",
+		mcp.WithDescription("Lists the languages a language museum is about, with ISO 639 codes, the museum's names in each language and Wordnik definitions of each language name. The returned word can be passed to the other Wordnik word tools"),
+		mcp.WithString("slug", mcp.Required(), mcp.Description("Museum slug, e.g. museum-of-the-basque-language")),
+		mcp.WithNumber("definitionLimit", mcp.Description(fmt.Sprintf("Wordnik definitions to fetch per language (at most %d); 0 skips the lookups, which then need no API key", maxLanguageDefinitions)), mcp.DefaultNumber(defaultLanguageDefinitions)),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetmuseumlanguagesHandler(cfg, store),
+	}
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/museums"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func callLanguages(t *testing.T, opts wordnikmock.Options, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	srv, err := wordnikmock.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	cfg := &config.APIConfig{BaseURL: ts.URL + "/v4"}
+	store := museums.NewStore(museums.NewIndex([]museums.Museum{{
+		Slug:      "museum-of-the-basque-language",
+		Name:      "Museum of the Basque Language",
+		Names:     map[string]string{"eu": "Euskararen Etxea"},
+		Languages: []string{"eus", "spa"},
+	}}))
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	res, err := GetmuseumlanguagesHandler(cfg, store)(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestGetMuseumLanguages(t *testing.T) {
+	res := callLanguages(t, wordnikmock.Options{}, map[string]any{"slug": "museum-of-the-basque-language"})
+	got, ok := res.StructuredContent.(museumLanguages)
+	if res.IsError || !ok || len(got.Languages) != 2 {
+		t.Fatalf("result %+v", res)
+	}
+	basque, spanish := got.Languages[0], got.Languages[1]
+	if basque.Word != "Basque" || basque.Names["eu"] != "Euskararen Etxea" || len(basque.Definitions) != defaultLanguageDefinitions {
+		t.Errorf("Basque: %+v", basque)
+	}
+	// No entry is not a failure.
+	if spanish.Word != "Spanish" || len(spanish.Definitions) != 0 || len(got.Failures) != 0 {
+		t.Errorf("Spanish: %+v, failures %+v", spanish, got.Failures)
+	}
+}
+
+func TestGetMuseumLanguagesFailure(t *testing.T) {
+	faults := []wordnikmock.Fault{{Kind: wordnikmock.Malformed, Pattern: "/word.json/Basque/definitions"}}
+	res := callLanguages(t, wordnikmock.Options{Faults: faults}, map[string]any{"slug": "museum-of-the-basque-language"})
+	got, ok := res.StructuredContent.(museumLanguages)
+	if res.IsError || !ok {
+		t.Fatalf("result %+v", res)
+	}
+	if len(got.Languages) != 2 || len(got.Languages[0].Definitions) != 0 {
+		t.Errorf("languages %+v", got.Languages)
+	}
+	if len(got.Failures) != 1 || got.Failures[0].Word != "Basque" || got.Failures[0].Code != client.CodeDecodeError {
+		t.Errorf("failures %+v", got.Failures)
+	}
+}
+
+func TestGetMuseumLanguagesBadInput(t *testing.T) {
+	for _, args := range []map[string]any{
+		{},
+		{"slug": "museum-of-the-basque-language", "definitionLimit": float64(-1)},
+		{"slug": "museum-of-the-basque-language", "definitionLimit": float64(maxLanguageDefinitions + 1)},
+	} {
+		res := callLanguages(t, wordnikmock.Options{}, args)
+		text := ""
+		if len(res.Content) > 0 {
+			text = res.Content[0].(mcp.TextContent).Text
+		}
+		if !res.IsError || !strings.HasPrefix(text, string(client.CodeBadInput)) {
+			t.Errorf("%v: %s", args, text)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+type countryCount struct {
+	Country string `json:"country"`
+	Museums int    `json:"museums"`
+}
+
+func ListmuseumcountriesHandler(store *museums.Store) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ix := store.Index()
+		result := make([]countryCount, 0)
+		for _, c := range ix.Countries() {
+			result = append(result, countryCount{Country: c, Museums: len(ix.ByCountry(c))})
+		}
+
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+
+		return mcp.NewToolResultStructured(map[string]any{"countries": result}, string(prettyJSON)), nil
+	}
+}
+
+func CreateListmuseumcountriesTool(store *museums.Store) models.Tool {
+	tool := mcp.NewTool("list_museum_countries",
+		mcp.WithDescription("Lists the countries in the language museums dataset with the number of museums in each"),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ListmuseumcountriesHandler(store),
+	}
+}
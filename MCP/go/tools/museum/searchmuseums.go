@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/museums"
+)
+
+// defaultSearchLimit is the number of results returned when limit is unset.
+const defaultSearchLimit = 10
+
+type searchHit struct {
+	museums.Detail
+	Score float64 `json:"score"`
+}
+
+type searchResults struct {
+	Total   int         `json:"total"`
+	Results []searchHit `json:"results"`
+}
+
+func SearchmuseumsHandler(store *museums.Store) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		text, _ := args["query"].(string)
+		q := museums.Query{}
+		q.Country, _ = args["country"].(string)
+		q.City, _ = args["city"].(string)
+		q.Category, _ = args["category"].(string)
+		q.Language, _ = args["language"].(string)
+		if val, ok := args["foundedFrom"].(float64); ok {
+			q.FoundedFrom = int(val)
+		}
+		if val, ok := args["foundedTo"].(float64); ok {
+			q.FoundedTo = int(val)
+		}
+		limit := defaultSearchLimit
+		if val, ok := args["limit"].(float64); ok && val > 0 {
+			limit = int(val)
+		}
+
+		matches := store.Index().Search(text, q)
+		result := searchResults{Total: len(matches), Results: []searchHit{}}
+		for i := 0; i < len(matches) && i < limit; i++ {
+			result.Results = append(result.Results, searchHit{Detail: matches[i].Museum.Detail(), Score: matches[i].Score})
+		}
+
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+
+		return mcp.NewToolResultStructured(result, string(prettyJSON)), nil
+	}
+}
+
+func categoryIDs() []string {
+	ids := make([]string, len(museums.Categories))
+	for i, c := range museums.Categories {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func CreateSearchmuseumsTool(store *museums.Store) models.Tool {
+	tool := mcp.NewTool("search_museums",
+		mcp.WithDescription("Searches the language museums dataset by free text, with typo-tolerant name matching and optional country, city, language and founding year filters"),
+		mcp.WithString("query", mcp.Description("Words to look for in the museum's names, languages, location or address. Leave empty to list every museum matching the filters")),
+		mcp.WithString("country", mcp.Description("Only museums in this country, as written in the dataset (see list_museum_countries) or as an ISO 3166-1 alpha-2 code such as \"DE\"")),
+		mcp.WithString("city", mcp.Description("Only museums in this city")),
+		mcp.WithString("category", mcp.Description("Only museums in this section of the museums list"), mcp.Enum(categoryIDs()...)),
+		mcp.WithString("language", mcp.Description("Only museums about this language, as an English name (\"Basque\") or ISO 639 code (\"eus\", \"eu\")")),
+		mcp.WithNumber("foundedFrom", mcp.Description("Only museums founded in or after this year")),
+		mcp.WithNumber("foundedTo", mcp.Description("Only museums founded in or before this year")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of results to return"), mcp.DefaultNumber(defaultSearchLimit)),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SearchmuseumsHandler(store),
+	}
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// Defaults of the frequency endpoint, per openapi.yml.
+const (
+	defaultStartYear = 1800
+	defaultEndYear   = 2012
+)
+
+// minFrequencyYear is the first year of the corpus; the last is the
+// current year. Years outside are rejected, since the series holds a count
+// for every year of the range.
+const minFrequencyYear = defaultStartYear
+
+// maxCompareWords bounds the number of words compared in one call.
+const maxCompareWords = 8
+
+// frequencyTTL is how long frequency summaries are cached. The corpus is
+// historical, so they change rarely.
+const frequencyTTL = 24 * time.Hour
+
+// frequencyComparison is the structured result of compare_word_frequency.
+type frequencyComparison struct {
+	StartYear int               `json:"startYear"`
+	EndYear   int               `json:"endYear"`
+	Normalize string            `json:"normalize"`
+	Series    []frequencySeries `json:"series"`
+	// Missing are the words the corpus has no entry for.
+	Missing []string `json:"missing,omitempty"`
+}
+
+func ComparefrequencyHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		words := stringList(args["words"])
+		if len(words) == 0 {
+			return client.ErrorResult(client.BadInput("missing required parameter: words")), nil
+		}
+		if len(words) > maxCompareWords {
+			return client.ErrorResult(client.BadInput("at most %d words can be compared, got %d", maxCompareWords, len(words))), nil
+		}
+		startYear, endYear := defaultStartYear, defaultEndYear
+		if val, ok := args["startYear"].(float64); ok {
+			startYear = int(val)
+		}
+		if val, ok := args["endYear"].(float64); ok {
+			endYear = int(val)
+		}
+		if lastYear := time.Now().Year(); startYear < minFrequencyYear || endYear > lastYear {
+			return client.ErrorResult(client.BadInput("years must be between %d and %d, got %d–%d", minFrequencyYear, lastYear, startYear, endYear)), nil
+		}
+		if endYear < startYear {
+			return client.ErrorResult(client.BadInput("endYear %d is before startYear %d", endYear, startYear)), nil
+		}
+		normalize := normalizeTotal
+		if val, ok := args["normalize"].(string); ok && val != "" {
+			normalize = val
+		}
+		if normalize != normalizeNone && normalize != normalizeTotal && normalize != normalizePeak {
+			return client.ErrorResult(client.BadInput("invalid normalize %q", normalize)), nil
+		}
+		chart := chartSparkline
+		if val, ok := args["chart"].(string); ok && val != "" {
+			chart = val
+		}
+		if chart != chartNone && chart != chartSparkline && chart != chartASCII {
+			return client.ErrorResult(client.BadInput("invalid chart %q", chart)), nil
+		}
+		query := url.Values{}
+		query.Set("startYear", strconv.Itoa(startYear))
+		query.Set("endYear", strconv.Itoa(endYear))
+		if val, ok := args["useCanonical"]; ok {
+			query.Set("useCanonical", client.QueryValue(val))
+		}
+
+		points := make([][]frequencyPoint, len(words))
+		errs := make([]error, len(words))
+		var wg sync.WaitGroup
+		for i, word := range words {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var summary struct {
+					Frequency []frequencyPoint `json:"frequency"`
+				}
+				errs[i] = client.GetCached(ctx, cfg, client.Path("word.json", word, "frequency"), query, frequencyTTL, &summary)
+				points[i] = summary.Frequency
+			}()
+		}
+		wg.Wait()
+		result := frequencyComparison{StartYear: startYear, EndYear: endYear, Normalize: normalize, Series: []frequencySeries{}}
+		for i, err := range errs {
+			switch {
+			case err == nil:
+				result.Series = append(result.Series, newFrequencySeries(words[i], points[i], startYear, endYear, normalize))
+			case client.CodeOf(err) == client.CodeNotFound:
+				result.Missing = append(result.Missing, words[i])
+			default:
+				return client.ErrorResult(&client.Error{Code: client.CodeOf(err), Message: fmt.Sprintf("frequency lookup for %q failed", words[i]), Err: err}), nil
+			}
+		}
+
+		return mcp.NewToolResultStructured(result, frequencyMarkdown(result, chart)), nil
+	}
+}
+
+// frequencyMarkdown renders the comparison as a markdown table followed by
+// the requested chart.
+func frequencyMarkdown(c frequencyComparison, chart string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Word frequency, %d–%d (normalized: %s)\n\n", c.StartYear, c.EndYear, c.Normalize)
+	b.WriteString("| Word | Total | Peak year | Trend (slope/yr) | Change |\n")
+	b.WriteString("|------|------:|----------:|-----------------:|-------:|\n")
+	var max float64
+	for _, s := range c.Series {
+		peak, change := "–", "–"
+		if s.PeakYear != 0 {
+			peak = strconv.Itoa(s.PeakYear)
+		}
+		if s.PercentChange != nil {
+			change = fmt.Sprintf("%+.1f%%", *s.PercentChange)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s | %+.4g | %s |\n", s.Word, s.Total, peak, s.Slope, change)
+		max = math.Max(max, s.PeakValue)
+	}
+	if len(c.Missing) > 0 {
+		fmt.Fprintf(&b, "\nNo frequency data for: %s\n", strings.Join(c.Missing, ", "))
+	}
+
+	switch chart {
+	case chartSparkline:
+		b.WriteString("\n")
+		for _, s := range c.Series {
+			fmt.Fprintf(&b, "`%s` %s\n\n", s.Word, sparkline(s.Values, max))
+		}
+	case chartASCII:
+		fmt.Fprintf(&b, "\n```\n%s```\n", asciiChart(c.Series, c.StartYear, c.EndYear, max))
+	}
+	return b.String()
+}
+
+// stringList accepts either a JSON array of strings or a comma-separated
+// string.
+func stringList(val any) []string {
+	var raw []string
+	switch v := val.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	case string:
+		raw = strings.Split(v, ",")
+	}
+	out := make([]string, 0, len(raw))
+	for _, s := range raw {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func CreateComparefrequencyTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("compare_word_frequency",
+		mcp.WithDescription("Compares the usage of several words over time: normalizes yearly counts, computes trends (slope, peak year, percent change) and renders a markdown table with an optional sparkline or ASCII chart"),
+		mcp.WithArray("words", mcp.Required(), mcp.WithStringItems(), mcp.Description(fmt.Sprintf("Words to compare (at most %d)", maxCompareWords))),
+		mcp.WithNumber("startYear", mcp.Description(fmt.Sprintf("Starting Year, from %d", minFrequencyYear)), mcp.DefaultNumber(defaultStartYear)),
+		mcp.WithNumber("endYear", mcp.Description("Ending Year, up to the current year"), mcp.DefaultNumber(defaultEndYear)),
+		mcp.WithString("normalize", mcp.Description("How yearly counts are normalized: 'total' divides by the word's count over the whole range, 'peak' by its busiest year, 'none' keeps raw counts"), mcp.Enum(normalizeTotal, normalizePeak, normalizeNone)),
+		mcp.WithString("chart", mcp.Description("Chart appended to the markdown output"), mcp.Enum(chartSparkline, chartASCII, chartNone)),
+		mcp.WithString("useCanonical", mcp.Description("If true will try to return the correct word root ('cats' -> 'cat'). If false returns exactly what was requested.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ComparefrequencyHandler(cfg),
+	}
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Normalization modes for frequency series.
+const (
+	normalizeNone  = "none"
+	normalizeTotal = "total" // share of the word's total count over the range
+	normalizePeak  = "peak"  // fraction of the word's peak year
+)
+
+// Chart styles for the markdown output.
+const (
+	chartNone      = "none"
+	chartSparkline = "sparkline"
+	chartASCII     = "ascii"
+)
+
+// maxChartWidth is the number of columns charts are bucketed into.
+const maxChartWidth = 60
+
+// asciiChartHeight is the number of rows in an ASCII chart.
+const asciiChartHeight = 10
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var chartMarkers = []rune("*+ox#@%&")
+
+// frequencyPoint is one entry of FrequencySummary.frequency. The API has
+// been seen to send the year both as a number and as a string, so it is
+// decoded leniently.
+type frequencyPoint struct {
+	Year  int
+	Count int64
+}
+
+func (p *frequencyPoint) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Year  json.RawMessage `json:"year"`
+		Count int64           `json:"count"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	year := strings.Trim(string(raw.Year), `"`)
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return fmt.Errorf("invalid frequency year %s", raw.Year)
+	}
+	p.Year, p.Count = y, raw.Count
+	return nil
+}
+
+// frequencySeries is one word's counts for every year in the range, with
+// missing years filled as zero.
+type frequencySeries struct {
+	Word      string    `json:"word"`
+	Counts    []int64   `json:"counts"`
+	Values    []float64 `json:"values"`
+	Total     int64     `json:"total"`
+	PeakYear  int       `json:"peakYear,omitempty"`
+	PeakValue float64   `json:"peakValue"`
+	// Slope is the least-squares trend of Values, in units per year.
+	Slope float64 `json:"slope"`
+	// PercentChange compares the last year of the range with the first; it is
+	// omitted when the first year has no occurrences.
+	PercentChange *float64 `json:"percentChange,omitempty"`
+}
+
+func newFrequencySeries(word string, points []frequencyPoint, startYear, endYear int, normalize string) frequencySeries {
+	s := frequencySeries{Word: word, Counts: make([]int64, endYear-startYear+1)}
+	for _, p := range points {
+		if p.Year >= startYear && p.Year <= endYear {
+			s.Counts[p.Year-startYear] += p.Count
+		}
+	}
+	var peak int64
+	for i, c := range s.Counts {
+		s.Total += c
+		if c > peak {
+			peak = c
+			s.PeakYear = startYear + i
+		}
+	}
+
+	divisor := 1.0
+	switch normalize {
+	case normalizeTotal:
+		divisor = float64(s.Total)
+	case normalizePeak:
+		divisor = float64(peak)
+	}
+	s.Values = make([]float64, len(s.Counts))
+	for i, c := range s.Counts {
+		if divisor > 0 {
+			s.Values[i] = float64(c) / divisor
+		}
+	}
+	if s.PeakYear != 0 {
+		s.PeakValue = s.Values[s.PeakYear-startYear]
+	}
+	s.Slope = slope(s.Values)
+	if first := s.Values[0]; first > 0 {
+		change := (s.Values[len(s.Values)-1] - first) / first * 100
+		s.PercentChange = &change
+	}
+	return s
+}
+
+// slope fits y = a + b*x over x = 0..n-1 and returns b.
+func slope(ys []float64) float64 {
+	n := float64(len(ys))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range ys {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
+// bucket averages values down to at most width columns.
+func bucket(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for i := range out {
+		lo := i * len(values) / width
+		hi := (i + 1) * len(values) / width
+		var sum float64
+		for _, v := range values[lo:hi] {
+			sum += v
+		}
+		out[i] = sum / float64(hi-lo)
+	}
+	return out
+}
+
+// sparkline renders values as a row of block characters scaled to max.
+func sparkline(values []float64, max float64) string {
+	var b strings.Builder
+	for _, v := range bucket(values, maxChartWidth) {
+		idx := 0
+		if max > 0 {
+			idx = int(math.Round(v / max * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// asciiChart plots every series on a shared y axis, one marker per word.
+func asciiChart(series []frequencySeries, startYear, endYear int, max float64) string {
+	var width int
+	columns := make([][]float64, len(series))
+	for i, s := range series {
+		columns[i] = bucket(s.Values, maxChartWidth)
+		width = len(columns[i])
+	}
+	grid := make([][]rune, asciiChartHeight)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", width))
+	}
+	for i, col := range columns {
+		marker := chartMarkers[i%len(chartMarkers)]
+		for x, v := range col {
+			if max <= 0 || v <= 0 {
+				continue
+			}
+			row := asciiChartHeight - 1 - int(math.Round(v/max*float64(asciiChartHeight-1)))
+			grid[row][x] = marker
+		}
+	}
+
+	label := func(v float64) string { return fmt.Sprintf("%10.4g", v) }
+	var b strings.Builder
+	for r, line := range grid {
+		prefix := strings.Repeat(" ", 10)
+		switch r {
+		case 0:
+			prefix = label(max)
+		case asciiChartHeight - 1:
+			prefix = label(0)
+		}
+		fmt.Fprintf(&b, "%s |%s\n", prefix, string(line))
+	}
+	fmt.Fprintf(&b, "%s +%s\n", strings.Repeat(" ", 10), strings.Repeat("-", width))
+	axis := fmt.Sprintf("%d%*d", startYear, width-4, endYear)
+	fmt.Fprintf(&b, "%s  %s\n", strings.Repeat(" ", 10), axis)
+	for i, s := range series {
+		fmt.Fprintf(&b, "%s  %c %s\n", strings.Repeat(" ", 10), chartMarkers[i%len(chartMarkers)], s.Word)
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func nearAll(a, b []float64) bool {
+	return slices.EqualFunc(a, b, near)
+}
+
+func TestNewFrequencySeries(t *testing.T) {
+	points := []frequencyPoint{
+		{Year: 1999, Count: 1000}, // before the range
+		{Year: 2000, Count: 10},
+		{Year: 2002, Count: 20},
+		{Year: 2002, Count: 10}, // the same year twice is summed
+		{Year: 2003, Count: 40},
+		{Year: 2005, Count: 1000}, // after the range
+	}
+	tests := []struct {
+		normalize string
+		values    []float64
+		peakValue float64
+		slope     float64
+	}{
+		{normalizeNone, []float64{10, 0, 30, 40}, 40, 12},
+		{normalizeTotal, []float64{0.125, 0, 0.375, 0.5}, 0.5, 0.15},
+		{normalizePeak, []float64{0.25, 0, 0.75, 1}, 1, 0.3},
+	}
+	for _, tt := range tests {
+		s := newFrequencySeries("cat", points, 2000, 2003, tt.normalize)
+		if !slices.Equal(s.Counts, []int64{10, 0, 30, 40}) || s.Total != 80 || s.PeakYear != 2003 {
+			t.Errorf("%s: counts %v, total %d, peak year %d", tt.normalize, s.Counts, s.Total, s.PeakYear)
+		}
+		if !nearAll(s.Values, tt.values) || !near(s.PeakValue, tt.peakValue) || !near(s.Slope, tt.slope) {
+			t.Errorf("%s: values %v, peak %v, slope %v; want %v, %v, %v", tt.normalize, s.Values, s.PeakValue, s.Slope, tt.values, tt.peakValue, tt.slope)
+		}
+		// Normalizing does not change the relative change.
+		if s.PercentChange == nil || !near(*s.PercentChange, 300) {
+			t.Errorf("%s: percent change %v", tt.normalize, s.PercentChange)
+		}
+	}
+}
+
+func TestNewFrequencySeriesEdges(t *testing.T) {
+	// No occurrences: no peak, no change, no division by zero.
+	s := newFrequencySeries("zzyzx", nil, 2000, 2002, normalizeTotal)
+	if s.Total != 0 || s.PeakYear != 0 || s.PercentChange != nil || !nearAll(s.Values, []float64{0, 0, 0}) || s.Slope != 0 {
+		t.Errorf("empty series %+v", s)
+	}
+	// A first year without occurrences has no percent change.
+	s = newFrequencySeries("cat", []frequencyPoint{{Year: 2001, Count: 5}}, 2000, 2001, normalizeNone)
+	if s.PercentChange != nil || !near(s.Slope, 5) {
+		t.Errorf("series %+v", s)
+	}
+	// A single year has no trend.
+	s = newFrequencySeries("cat", []frequencyPoint{{Year: 2000, Count: 5}}, 2000, 2000, normalizeNone)
+	if s.Slope != 0 || s.PeakYear != 2000 || *s.PercentChange != 0 {
+		t.Errorf("series %+v", s)
+	}
+	// The earliest of equal peaks counts.
+	s = newFrequencySeries("cat", []frequencyPoint{{Year: 2000, Count: 5}, {Year: 2001, Count: 5}}, 2000, 2001, normalizeNone)
+	if s.PeakYear != 2000 {
+		t.Errorf("peak year %d", s.PeakYear)
+	}
+}
+
+func TestFrequencyPointJSON(t *testing.T) {
+	var points []frequencyPoint
+	if err := json.Unmarshal([]byte(`[{"year": "2000", "count": 3}, {"year": 2001, "count": 4}]`), &points); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(points, []frequencyPoint{{2000, 3}, {2001, 4}}) {
+		t.Errorf("points %+v", points)
+	}
+	for _, data := range []string{`{"year": "c. 2000", "count": 1}`, `{"count": 1}`, `[]`} {
+		var p frequencyPoint
+		if err := json.Unmarshal([]byte(data), &p); err == nil {
+			t.Errorf("%s decoded as %+v", data, p)
+		}
+	}
+}
+
+func TestBucket(t *testing.T) {
+	if got := bucket([]float64{1, 2, 3}, 5); !nearAll(got, []float64{1, 2, 3}) {
+		t.Errorf("short series bucketed: %v", got)
+	}
+	if got := bucket([]float64{1, 3, 5, 7, 9, 11}, 3); !nearAll(got, []float64{2, 6, 10}) {
+		t.Errorf("bucket = %v", got)
+	}
+	if got := bucket(make([]float64, 213), maxChartWidth); len(got) != maxChartWidth {
+		t.Errorf("%d columns", len(got))
+	}
+	if got := sparkline([]float64{0, 1, 2, 4}, 4); got != "▁▃▅█" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline([]float64{0, 0}, 0); got != "▁▁" {
+		t.Errorf("sparkline of zeros = %q", got)
+	}
+}
+
+func TestCompareFrequencyYears(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{})
+	handler := ComparefrequencyHandler(cfg)
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		var req mcp.CallToolRequest
+		req.Params.Arguments = args
+		res, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	next := float64(time.Now().Year() + 1)
+	for _, years := range [][2]float64{{minFrequencyYear - 1, 2000}, {2000, next}, {2005, 2000}} {
+		res := call(map[string]any{"words": []any{"cat"}, "startYear": years[0], "endYear": years[1]})
+		text := res.Content[0].(mcp.TextContent).Text
+		if !res.IsError || !strings.HasPrefix(text, string(client.CodeBadInput)) {
+			t.Errorf("years %v: %s", years, text)
+		}
+	}
+
+	res := call(map[string]any{"words": []any{"cat", "zzyzx"}, "startYear": float64(2000), "endYear": float64(2005), "normalize": normalizeNone})
+	c, ok := res.StructuredContent.(frequencyComparison)
+	if res.IsError || !ok {
+		t.Fatalf("result %+v", res)
+	}
+	if len(c.Series) != 1 || c.Series[0].Total != 940 || c.Series[0].PeakYear != 2005 || !slices.Equal(c.Missing, []string{"zzyzx"}) {
+		t.Errorf("comparison %+v", c)
+	}
+}
@@ -2,86 +2,141 @@ package tools
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"net/url"
+	"sync"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/audio"
+	"github.com/wordnik/mcp-server/client"
 	"github.com/wordnik/mcp-server/config"
 	"github.com/wordnik/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// Download modes of word_audio.
+const (
+	downloadNone    = "none"
+	downloadContent = "content" // files returned as MCP audio content
+	downloadProxy   = "proxy"   // files linked through /audio/{id} in HTTP mode
+)
+
+// maxAudioDownloads bounds the files downloaded in one call.
+const maxAudioDownloads = 5
+
+// audioFile is an AudioFile with the outcome of its download, if any.
+type audioFile struct {
+	models.AudioFile
+	ProxyURL      string     `json:"proxyUrl,omitempty"`
+	ProxyExpires  *time.Time `json:"proxyExpires,omitempty"`
+	DownloadError string     `json:"downloadError,omitempty"`
+}
+
 func GetaudioHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
 		}
 		wordVal, ok := args["word"]
 		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: word"), nil
+			return client.ErrorResult(client.BadInput("missing required path parameter: word")), nil
 		}
 		word, ok := wordVal.(string)
 		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: word"), nil
-		}
-		queryParams := make([]string, 0)
-		if val, ok := args["useCanonical"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("useCanonical=%v", val))
+			return client.ErrorResult(client.BadInput("invalid path parameter: word")), nil
 		}
-		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
+		download := downloadNone
+		if val, ok := args["download"].(string); ok && val != "" {
+			download = val
 		}
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			queryParams = append(queryParams, fmt.Sprintf("api_key=%s", cfg.APIKey))
+		if download != downloadNone && download != downloadContent && download != downloadProxy {
+			return client.ErrorResult(client.BadInput("invalid download %q", download)), nil
 		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
+		proxyURL, proxied := audio.ProxyURL(ctx)
+		if download == downloadProxy && !proxied {
+			return client.ErrorResult(client.BadInput("download=proxy is only available in HTTP/HTTPS mode; use download=content")), nil
 		}
-		url := fmt.Sprintf("%s/word.json/%s/audio%s", cfg.BaseURL, word, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			// API key already added to query string
+		query := url.Values{}
+		if val, ok := args["useCanonical"]; ok {
+			query.Set("useCanonical", client.QueryValue(val))
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		if val, ok := args["limit"]; ok {
+			query.Set("limit", client.QueryValue(val))
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
+		var result []models.AudioFile
+		used, err := client.GetWord(ctx, cfg, word, "audio", query, &result)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
+			return client.WordErrorResult(ctx, cfg, word, err), nil
 		}
 
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+		files := make([]audioFile, len(result))
+		for i, f := range result {
+			files[i].AudioFile = f
 		}
-		// Use properly typed response
-		var result []AudioFile
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		var contents []mcp.Content
+		if download != downloadNone {
+			contents = downloadAudio(ctx, files, download, proxyURL)
 		}
 
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		prettyJSON, err := json.MarshalIndent(files, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
 		}
 
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		res := client.WordTextResult(word, used, string(prettyJSON))
+		res.Content = append(res.Content, contents...)
+		return res, nil
+	}
+}
+
+// downloadAudio downloads the first maxAudioDownloads files concurrently.
+// In content mode it returns them as audio content, in proxy mode it links
+// them under proxyURL. A failed download is recorded on its file rather
+// than failing the call, since the metadata is still useful.
+func downloadAudio(ctx context.Context, files []audioFile, mode, proxyURL string) []mcp.Content {
+	contents := make([]mcp.Content, len(files))
+	var wg sync.WaitGroup
+	for i := range files {
+		f := &files[i]
+		switch {
+		case f.Fileurl == "":
+			f.DownloadError = "no file URL"
+			continue
+		case i >= maxAudioDownloads:
+			f.DownloadError = fmt.Sprintf("not downloaded: at most %d files are downloaded per call", maxAudioDownloads)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if mode == downloadProxy {
+				id, expires, err := audio.Link(ctx, f.Fileurl)
+				if err != nil {
+					f.DownloadError = err.Error()
+					return
+				}
+				f.ProxyURL, f.ProxyExpires = proxyURL+"/audio/"+id, &expires
+				return
+			}
+			file, err := audio.Fetch(ctx, f.Fileurl)
+			if err != nil {
+				f.DownloadError = err.Error()
+				return
+			}
+			contents[i] = mcp.NewAudioContent(base64.StdEncoding.EncodeToString(file.Data), file.MIMEType)
+		}()
+	}
+	wg.Wait()
+	var out []mcp.Content
+	for _, c := range contents {
+		if c != nil {
+			out = append(out, c)
+		}
 	}
+	return out
 }
 
 func CreateGetaudioTool(cfg *config.APIConfig) models.Tool {
@@ -90,6 +145,7 @@ func CreateGetaudioTool(cfg *config.APIConfig) models.Tool {
 		mcp.WithString("word", mcp.Required(), mcp.Description("Word to get audio for.")),
 		mcp.WithString("useCanonical", mcp.Description("If true will try to return the correct word root ('cats' -> 'cat'). If false returns exactly what was requested.")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of results to return")),
+		mcp.WithString("download", mcp.Description(fmt.Sprintf("Whether to download the files, which expire soon: 'content' returns them as audio content, 'proxy' links them through this server (HTTP mode only), 'none' returns metadata only. At most %d files are downloaded.", maxAudioDownloads)), mcp.Enum(downloadNone, downloadContent, downloadProxy)),
 	)
 
 	return models.Tool{
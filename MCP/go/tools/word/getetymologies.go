@@ -3,73 +3,36 @@ package tools
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"net/url"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
 	"github.com/wordnik/mcp-server/config"
 	"github.com/wordnik/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetetymologiesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
 		}
 		wordVal, ok := args["word"]
 		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: word"), nil
+			return client.ErrorResult(client.BadInput("missing required path parameter: word")), nil
 		}
 		word, ok := wordVal.(string)
 		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: word"), nil
+			return client.ErrorResult(client.BadInput("invalid path parameter: word")), nil
 		}
-		queryParams := make([]string, 0)
+		query := url.Values{}
 		if val, ok := args["useCanonical"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("useCanonical=%v", val))
-		}
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			queryParams = append(queryParams, fmt.Sprintf("api_key=%s", cfg.APIKey))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/word.json/%s/etymologies%s", cfg.BaseURL, word, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			// API key already added to query string
+			query.Set("useCanonical", client.QueryValue(val))
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result []string
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		used, err := client.GetWord(ctx, cfg, word, "etymologies", query, &result)
+		if err != nil {
+			return client.WordErrorResult(ctx, cfg, word, err), nil
 		}
 
 		prettyJSON, err := json.MarshalIndent(result, "", "  ")
@@ -77,7 +40,7 @@ func GetetymologiesHandler(cfg *config.APIConfig) func(ctx context.Context, requ
 			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
 		}
 
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return client.WordTextResult(word, used, string(prettyJSON)), nil
 	}
 }
 
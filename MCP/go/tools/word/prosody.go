@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/wordnik/mcp-server/models"
+)
+
+// Stress marks used in stress patterns: primary, secondary and unstressed.
+const (
+	stressPrimary   = '1'
+	stressSecondary = '2'
+	stressNone      = '0'
+)
+
+var verseToken = regexp.MustCompile(`[\p{L}]+(?:['’][\p{L}]+)*`)
+
+// tokenizeVerse splits a line of verse into lowercase words, keeping inner
+// apostrophes ("o'er", "heaven's").
+func tokenizeVerse(line string) []string {
+	tokens := verseToken.FindAllString(line, -1)
+	for i, t := range tokens {
+		tokens[i] = strings.ToLower(strings.ReplaceAll(t, "’", "'"))
+	}
+	return tokens
+}
+
+// arpabetPhones splits an arpabet pronunciation into phones, dropping any
+// punctuation the dictionary wraps it in.
+func arpabetPhones(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	phones := make([]string, 0, len(fields))
+	for _, f := range fields {
+		phones = append(phones, strings.ToUpper(f))
+	}
+	return phones
+}
+
+// isVowelPhone reports whether an arpabet phone is a vowel, which in
+// arpabet always carries a stress digit.
+func isVowelPhone(phone string) bool {
+	if phone == "" {
+		return false
+	}
+	last := phone[len(phone)-1]
+	return last == stressPrimary || last == stressSecondary || last == stressNone
+}
+
+// arpabetStress returns the stress pattern of a pronunciation, one digit per
+// syllable, e.g. "010" for "AH0 B AH1 N D AH0 N S" (abundance).
+func arpabetStress(phones []string) string {
+	var b strings.Builder
+	for _, p := range phones {
+		if isVowelPhone(p) {
+			b.WriteByte(p[len(p)-1])
+		}
+	}
+	return b.String()
+}
+
+// arpabetRhymeKey returns the phones from the last vowel with primary stress
+// to the end with stress digits removed, e.g. "EY-SH-AH-N" for "nation" and
+// "IH-K-SH-AH-N-EH-R-IY" for "dictionary", whose later vowel with secondary
+// stress does not count. Words rhyme when their keys match. Words without
+// primary stress use their last vowel with secondary stress, and
+// unstressed words their last vowel.
+func arpabetRhymeKey(phones []string) string {
+	start := -1
+	for _, stress := range []byte{stressPrimary, stressSecondary, stressNone} {
+		for i, p := range phones {
+			if isVowelPhone(p) && p[len(p)-1] == stress {
+				start = i
+			}
+		}
+		if start != -1 {
+			break
+		}
+	}
+	if start == -1 {
+		return ""
+	}
+	key := make([]string, 0, len(phones)-start)
+	for _, p := range phones[start:] {
+		key = append(key, strings.TrimRight(p, "012"))
+	}
+	return strings.Join(key, "-")
+}
+
+// hyphenationStress derives a stress pattern from hyphenation syllables,
+// whose type is "stress" or "secondary stress" for stressed syllables.
+func hyphenationStress(syllables []models.Syllable) string {
+	var b strings.Builder
+	for _, s := range syllables {
+		switch s.TypeField {
+		case "stress":
+			b.WriteByte(stressPrimary)
+		case "secondary stress":
+			b.WriteByte(stressSecondary)
+		default:
+			b.WriteByte(stressNone)
+		}
+	}
+	return b.String()
+}
+
+var vowelGroups = regexp.MustCompile(`[aeiouy]+`)
+
+// estimateSyllables counts syllables from spelling alone, for words the
+// dictionary does not know. It counts vowel groups, discounting a silent
+// final "e" but not the "le" of "table".
+func estimateSyllables(word string) int {
+	w := strings.ToLower(word)
+	n := len(vowelGroups.FindAllString(w, -1))
+	if strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && n > 1 {
+		n--
+	}
+	return max(n, 1)
+}
+
+// spellingRhymeKey approximates a rhyme key from spelling: the last vowel
+// group and everything after it, ignoring a silent final "e".
+func spellingRhymeKey(word string) string {
+	w := strings.ToLower(word)
+	trimmed := w
+	if strings.HasSuffix(w, "e") && len(w) > 2 {
+		trimmed = w[:len(w)-1]
+	}
+	locs := vowelGroups.FindAllStringIndex(trimmed, -1)
+	if len(locs) == 0 {
+		return w
+	}
+	return w[locs[len(locs)-1][0]:]
+}
+
+// meter is a metrical foot pattern repeated across a line, with '0' for an
+// unstressed and '1' for a stressed position.
+type meter struct {
+	Name string
+	Foot string
+}
+
+var meters = []meter{
+	{"iambic", "01"},
+	{"trochaic", "10"},
+	{"anapestic", "001"},
+	{"dactylic", "100"},
+}
+
+var lineLengths = map[int]string{
+	1: "monometer", 2: "dimeter", 3: "trimeter", 4: "tetrameter",
+	5: "pentameter", 6: "hexameter", 7: "heptameter", 8: "octameter",
+}
+
+// guessMeter returns the meter that best fits a line's stress pattern and
+// the fraction of syllables that match it. Monosyllables are given '?' stress
+// and match either position; secondary stress matches a stressed position.
+func guessMeter(pattern string) (string, float64) {
+	if pattern == "" {
+		return "", 0
+	}
+	best, bestScore := "", -1.0
+	for _, m := range meters {
+		matched := 0
+		for i := 0; i < len(pattern); i++ {
+			want := m.Foot[i%len(m.Foot)]
+			got := pattern[i]
+			if got == '?' || got == want || (got == stressSecondary && want == stressPrimary) {
+				matched++
+			}
+		}
+		score := float64(matched) / float64(len(pattern))
+		if score > bestScore {
+			feet := (len(pattern) + len(m.Foot) - 1) / len(m.Foot)
+			best, bestScore = m.Name, score
+			if length, ok := lineLengths[feet]; ok {
+				best += " " + length
+			}
+		}
+	}
+	return best, bestScore
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArpabetRhymeKey(t *testing.T) {
+	tests := []struct {
+		word, pron, want string
+	}{
+		{"nation", "N EY1 SH AH0 N", "EY-SH-AH-N"},
+		{"station", "S T EY1 SH AH0 N", "EY-SH-AH-N"},
+		// Secondary stress after the primary stress does not count.
+		{"dictionary", "D IH1 K SH AH0 N EH2 R IY0", "IH-K-SH-AH-N-EH-R-IY"},
+		{"fictionary", "F IH1 K SH AH0 N EH2 R IY0", "IH-K-SH-AH-N-EH-R-IY"},
+		// Secondary stress before it does not either.
+		{"understand", "AH2 N D ER0 S T AE1 N D", "AE-N-D"},
+		// Without primary stress, the last secondary, then the last vowel.
+		{"into", "IH2 N T UW2", "UW"},
+		{"the", "DH AH0", "AH"},
+		{"of", "AH0 V", "AH-V"},
+		{"hm", "HH M", ""},
+		{"", "", ""},
+		// Dictionary punctuation and case are ignored.
+		{"nation", "(n ey1 sh ah0 n)", "EY-SH-AH-N"},
+	}
+	for _, tt := range tests {
+		if got := arpabetRhymeKey(arpabetPhones(tt.pron)); got != tt.want {
+			t.Errorf("arpabetRhymeKey(%s: %q) = %q, want %q", tt.word, tt.pron, got, tt.want)
+		}
+	}
+}
+
+func TestArpabetStress(t *testing.T) {
+	tests := []struct {
+		word, pron, want string
+	}{
+		{"abundance", "AH0 B AH1 N D AH0 N S", "010"},
+		{"dictionary", "D IH1 K SH AH0 N EH2 R IY0", "1020"},
+		{"understand", "AH2 N D ER0 S T AE1 N D", "201"},
+		{"cat", "K AE1 T", "1"},
+		{"hm", "HH M", ""},
+	}
+	for _, tt := range tests {
+		if got := arpabetStress(arpabetPhones(tt.pron)); got != tt.want {
+			t.Errorf("arpabetStress(%s: %q) = %q, want %q", tt.word, tt.pron, got, tt.want)
+		}
+	}
+}
+
+func TestGuessMeter(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		score   float64
+	}{
+		{"0101010101", "iambic pentameter", 1},
+		{"10101010", "trochaic tetrameter", 1},
+		{"001001", "anapestic dimeter", 1},
+		{"100100100", "dactylic trimeter", 1},
+		// Secondary stress fills a stressed position and monosyllables
+		// either.
+		{"0201", "iambic dimeter", 1},
+		{"?1?1?1", "iambic trimeter", 1},
+		{"0110", "anapestic dimeter", 0.75},
+		// An incomplete last foot counts as a foot.
+		{"010", "iambic dimeter", 1},
+		// Past eight feet the length has no name.
+		{strings.Repeat("01", 9), "iambic", 1},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if got, score := guessMeter(tt.pattern); got != tt.want || score != tt.score {
+			t.Errorf("guessMeter(%q) = %q, %v, want %q, %v", tt.pattern, got, score, tt.want, tt.score)
+		}
+	}
+}
+
+func TestEstimateSyllables(t *testing.T) {
+	tests := map[string]int{
+		"cat":         1,
+		"cake":        1,
+		"the":         1,
+		"table":       2,
+		"Beautiful":   3,
+		"rhythm":      1,
+		"serendipity": 5,
+		"brr":         1,
+		"":            1,
+		"people":      2,
+	}
+	for word, want := range tests {
+		if got := estimateSyllables(word); got != want {
+			t.Errorf("estimateSyllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestSpellingRhymeKey(t *testing.T) {
+	tests := map[string]string{
+		"cake":  "ake",
+		"Lake":  "ake",
+		"night": "ight",
+		"table": "able",
+		"day":   "ay",
+		"the":   "the",
+		"brr":   "brr",
+		"be":    "e",
+	}
+	for word, want := range tests {
+		if got := spellingRhymeKey(word); got != want {
+			t.Errorf("spellingRhymeKey(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
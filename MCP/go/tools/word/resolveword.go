@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+func ResolvewordHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		wordVal, ok := args["word"]
+		if !ok {
+			return client.ErrorResult(client.BadInput("missing required parameter: word")), nil
+		}
+		word, ok := wordVal.(string)
+		if !ok || word == "" {
+			return client.ErrorResult(client.BadInput("invalid parameter: word")), nil
+		}
+		includeSuggestions := config.ParseBool(client.QueryValue(args["includeSuggestions"]))
+
+		result, err := client.Resolve(ctx, cfg, word, includeSuggestions)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+
+		return mcp.NewToolResultStructured(result, string(prettyJSON)), nil
+	}
+}
+
+func CreateResolvewordTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("resolve_word",
+		mcp.WithDescription("Resolves a possibly misspelled or inflected word: returns its canonical form ('cats' -> 'cat'), whether the dictionary has entries for it, and spelling suggestions when it does not"),
+		mcp.WithString("word", mcp.Required(), mcp.Description("Word to resolve")),
+		mcp.WithString("includeSuggestions", mcp.Description("If true, return spelling suggestions even when the word has entries")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ResolvewordHandler(cfg),
+	}
+}
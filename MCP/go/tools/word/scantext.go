@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// maxScanWords bounds the number of distinct words looked up per call.
+const maxScanWords = 100
+
+// scanConcurrency is the number of words looked up in parallel.
+const scanConcurrency = 4
+
+// scanTTL is how long pronunciation and hyphenation lookups are cached.
+const scanTTL = 24 * time.Hour
+
+// Sources of a scanned word's syllables and stress.
+const (
+	sourceArpabet     = "arpabet"
+	sourceHyphenation = "hyphenation"
+	sourceSpelling    = "spelling"
+)
+
+type scannedWord struct {
+	Word        string           `json:"word"`
+	Syllables   int              `json:"syllables"`
+	Hyphenation []string         `json:"hyphenation,omitempty"`
+	Stress      string           `json:"stress,omitempty"`
+	RhymeKeys   []string         `json:"rhymeKeys"`
+	Source      string           `json:"source"`
+	LookupError client.ErrorCode `json:"lookupError,omitempty"`
+}
+
+type scannedLine struct {
+	Text      string        `json:"text"`
+	Words     []scannedWord `json:"words"`
+	Syllables int           `json:"syllables"`
+	// Stress has one mark per syllable: 1 primary, 2 secondary, 0 unstressed
+	// and ? for monosyllables and words whose stress is unknown.
+	Stress    string   `json:"stress"`
+	Meter     string   `json:"meter,omitempty"`
+	MeterFit  float64  `json:"meterFit"`
+	RhymeKeys []string `json:"rhymeKeys"`
+}
+
+func ScantextHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		text, ok := args["text"].(string)
+		if !ok || strings.TrimSpace(text) == "" {
+			return client.ErrorResult(client.BadInput("missing required parameter: text")), nil
+		}
+		offline := config.ParseBool(client.QueryValue(args["offline"]))
+
+		var lines []string
+		unique := make(map[string]bool)
+		var order []string
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			lines = append(lines, strings.TrimSpace(line))
+			for _, w := range tokenizeVerse(line) {
+				if !unique[w] {
+					unique[w] = true
+					order = append(order, w)
+				}
+			}
+		}
+		if len(order) > maxScanWords {
+			return client.ErrorResult(client.BadInput("text has %d distinct words, at most %d are supported", len(order), maxScanWords)), nil
+		}
+
+		scanned := make(map[string]scannedWord, len(order))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, scanConcurrency)
+		for _, w := range order {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				sw := scanWord(ctx, cfg, w, offline)
+				mu.Lock()
+				scanned[w] = sw
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		result := make([]scannedLine, len(lines))
+		for i, line := range lines {
+			result[i] = scanLine(line, scanned)
+		}
+		return mcp.NewToolResultStructured(map[string]any{"lines": result}, scanMarkdown(result)), nil
+	}
+}
+
+// scanWord looks up a word's pronunciations and hyphenation, falling back to
+// spelling heuristics when the dictionary has no data or offline is set.
+func scanWord(ctx context.Context, cfg *config.APIConfig, word string, offline bool) scannedWord {
+	sw := scannedWord{Word: word, RhymeKeys: []string{}}
+	if !offline {
+		query := url.Values{}
+		query.Set("typeFormat", "arpabet")
+		var prons []models.TextPron
+		if err := client.GetCached(ctx, cfg, client.Path("word.json", word, "pronunciations"), query, scanTTL, &prons); err != nil && client.CodeOf(err) != client.CodeNotFound {
+			sw.LookupError = client.CodeOf(err)
+		}
+		seen := make(map[string]bool)
+		for _, p := range prons {
+			if p.Rawtype != "" && p.Rawtype != "arpabet" {
+				continue
+			}
+			phones := arpabetPhones(p.Raw)
+			stress := arpabetStress(phones)
+			if stress == "" {
+				continue
+			}
+			if sw.Stress == "" {
+				sw.Stress, sw.Syllables, sw.Source = stress, len(stress), sourceArpabet
+			}
+			if key := arpabetRhymeKey(phones); key != "" && !seen[key] {
+				seen[key] = true
+				sw.RhymeKeys = append(sw.RhymeKeys, key)
+			}
+		}
+
+		var syllables []models.Syllable
+		if err := client.GetCached(ctx, cfg, client.Path("word.json", word, "hyphenation"), nil, scanTTL, &syllables); err != nil && client.CodeOf(err) != client.CodeNotFound && sw.LookupError == "" {
+			sw.LookupError = client.CodeOf(err)
+		}
+		for _, s := range syllables {
+			sw.Hyphenation = append(sw.Hyphenation, s.Text)
+		}
+		if sw.Source == "" && len(syllables) > 0 {
+			sw.Stress, sw.Syllables, sw.Source = hyphenationStress(syllables), len(syllables), sourceHyphenation
+		}
+	}
+	if sw.Source == "" {
+		sw.Syllables, sw.Source = estimateSyllables(word), sourceSpelling
+	}
+	if len(sw.RhymeKeys) == 0 {
+		sw.RhymeKeys = append(sw.RhymeKeys, spellingRhymeKey(word))
+	}
+	return sw
+}
+
+func scanLine(text string, scanned map[string]scannedWord) scannedLine {
+	line := scannedLine{Text: text, Words: []scannedWord{}, RhymeKeys: []string{}}
+	var stress strings.Builder
+	for _, w := range tokenizeVerse(text) {
+		sw := scanned[w]
+		line.Words = append(line.Words, sw)
+		line.Syllables += sw.Syllables
+		if sw.Syllables == 1 || sw.Stress == "" {
+			stress.WriteString(strings.Repeat("?", sw.Syllables))
+		} else {
+			stress.WriteString(sw.Stress)
+		}
+	}
+	line.Stress = stress.String()
+	line.Meter, line.MeterFit = guessMeter(line.Stress)
+	if n := len(line.Words); n > 0 {
+		line.RhymeKeys = line.Words[n-1].RhymeKeys
+	}
+	return line
+}
+
+func scanMarkdown(lines []scannedLine) string {
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "> %s\n\n", l.Text)
+		fmt.Fprintf(&b, "- Syllables: %d\n", l.Syllables)
+		fmt.Fprintf(&b, "- Stress: `%s`\n", l.Stress)
+		if l.Meter != "" {
+			fmt.Fprintf(&b, "- Meter: %s (%.0f%% fit)\n", l.Meter, l.MeterFit*100)
+		}
+		fmt.Fprintf(&b, "- Rhyme keys: %s\n", strings.Join(l.RhymeKeys, ", "))
+		parts := make([]string, len(l.Words))
+		for j, w := range l.Words {
+			label := w.Word
+			if len(w.Hyphenation) > 1 {
+				label = strings.Join(w.Hyphenation, "·")
+			}
+			parts[j] = fmt.Sprintf("%s (%d)", label, w.Syllables)
+		}
+		fmt.Fprintf(&b, "- Words: %s\n", strings.Join(parts, " "))
+	}
+	return b.String()
+}
+
+func CreateScantextTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("scan_text",
+		mcp.WithDescription("Scans verse: for each line returns syllable counts, the stress pattern parsed from arpabet pronunciations (falling back to hyphenation, then spelling), a best-fit meter and candidate rhyme keys"),
+		mcp.WithString("text", mcp.Required(), mcp.Description("One or more lines of verse, separated by newlines")),
+		mcp.WithString("offline", mcp.Description("If true, skip dictionary lookups and estimate syllables and rhymes from spelling alone")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ScantextHandler(cfg),
+	}
+}
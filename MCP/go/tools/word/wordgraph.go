@@ -0,0 +1,312 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// Bounds and defaults of word_graph.
+const (
+	defaultGraphDepth    = 2
+	maxGraphDepth        = 3
+	defaultGraphNodes    = 50
+	maxGraphNodes        = 200
+	defaultGraphPerType  = 10
+	maxGraphPerType      = 50
+	graphConcurrency     = 4
+	defaultGraphRelation = "synonym"
+)
+
+// Output formats of word_graph.
+const (
+	graphJSON    = "json"
+	graphDOT     = "dot"
+	graphMermaid = "mermaid"
+)
+
+// relatedTTL is how long related words are cached. Expansions of nearby
+// words ask for the same ones.
+const relatedTTL = 24 * time.Hour
+
+// symmetricRelations are the relationship types that hold both ways, so
+// that an edge and its reverse are one undirected edge.
+var symmetricRelations = map[string]bool{
+	"synonym": true, "antonym": true, "equivalent": true, "variant": true, "rhyme": true, "same-context": true,
+}
+
+// wordGraph is the structured result of word_graph.
+type wordGraph struct {
+	Root              string         `json:"root"`
+	Depth             int            `json:"depth"`
+	RelationshipTypes []string       `json:"relationshipTypes"`
+	Nodes             []graphNode    `json:"nodes"`
+	Edges             []graphEdge    `json:"edges"`
+	Truncated         bool           `json:"truncated"`
+	Failures          []graphFailure `json:"failures,omitempty"`
+}
+
+type graphNode struct {
+	Word string `json:"word"`
+	// Depth is the number of hops from the root.
+	Depth int `json:"depth"`
+}
+
+type graphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Type     string `json:"type"`
+	Directed bool   `json:"directed"`
+}
+
+// graphFailure is a word whose related words could not be fetched.
+type graphFailure struct {
+	Word  string           `json:"word"`
+	Code  client.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+func WordgraphHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		word, _ := args["word"].(string)
+		if word = strings.TrimSpace(word); word == "" {
+			return client.ErrorResult(client.BadInput("missing required parameter: word")), nil
+		}
+		types := stringList(args["relationshipTypes"])
+		if len(types) == 0 {
+			types = []string{defaultGraphRelation}
+		}
+		for _, t := range types {
+			if !slices.Contains(models.RelationshipTypes, t) {
+				return client.ErrorResult(client.BadInput("invalid relationship type %q", t)), nil
+			}
+		}
+		depth := defaultGraphDepth
+		if val, ok := args["depth"].(float64); ok {
+			depth = int(val)
+		}
+		if depth < 1 || depth > maxGraphDepth {
+			return client.ErrorResult(client.BadInput("depth must be between 1 and %d, got %d", maxGraphDepth, depth)), nil
+		}
+		maxNodes := defaultGraphNodes
+		if val, ok := args["maxNodes"].(float64); ok {
+			maxNodes = int(val)
+		}
+		if maxNodes < 1 || maxNodes > maxGraphNodes {
+			return client.ErrorResult(client.BadInput("maxNodes must be between 1 and %d, got %d", maxGraphNodes, maxNodes)), nil
+		}
+		perType := defaultGraphPerType
+		if val, ok := args["limitPerRelationshipType"].(float64); ok {
+			perType = int(val)
+		}
+		if perType < 1 || perType > maxGraphPerType {
+			return client.ErrorResult(client.BadInput("limitPerRelationshipType must be between 1 and %d, got %d", maxGraphPerType, perType)), nil
+		}
+		format := graphJSON
+		if val, ok := args["format"].(string); ok && val != "" {
+			format = val
+		}
+		if format != graphJSON && format != graphDOT && format != graphMermaid {
+			return client.ErrorResult(client.BadInput("invalid format %q", format)), nil
+		}
+
+		query := url.Values{}
+		query.Set("relationshipTypes", strings.Join(types, ","))
+		query.Set("limitPerRelationshipType", strconv.Itoa(perType))
+		graph, err := expandGraph(ctx, cfg, word, types, depth, maxNodes, query)
+		if err != nil {
+			return client.WordErrorResult(ctx, cfg, word, err), nil
+		}
+
+		var text string
+		switch format {
+		case graphDOT:
+			text = graphDOTText(graph)
+		case graphMermaid:
+			text = graphMermaidText(graph)
+		default:
+			data, err := json.MarshalIndent(graph, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+			}
+			text = string(data)
+		}
+		return mcp.NewToolResultStructured(graph, text), nil
+	}
+}
+
+// expandGraph walks the related words of root breadth first, one level at a
+// time, up to depth hops and maxNodes nodes. The words of a level are
+// fetched concurrently through the response cache, and the results applied
+// in order, so that the graph does not depend on timing. Words without
+// related words are leaves; other failures are recorded, except for the
+// root, whose failure is returned.
+func expandGraph(ctx context.Context, cfg *config.APIConfig, root string, types []string, depth, maxNodes int, query url.Values) (wordGraph, error) {
+	g := wordGraph{Root: root, Depth: depth, RelationshipTypes: types, Nodes: []graphNode{{Word: root}}, Edges: []graphEdge{}}
+	seen := map[string]bool{strings.ToLower(root): true}
+	edges := map[[3]string]bool{}
+	frontier := []string{root}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		related := make([][]models.Related, len(frontier))
+		errs := make([]error, len(frontier))
+		sem := make(chan struct{}, graphConcurrency)
+		var wg sync.WaitGroup
+		for i, word := range frontier {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				errs[i] = client.GetCached(ctx, cfg, client.Path("word.json", word, "relatedWords"), query, relatedTTL, &related[i])
+			}()
+		}
+		wg.Wait()
+
+		var next []string
+		for i, word := range frontier {
+			if err := errs[i]; err != nil {
+				if level == 0 {
+					return wordGraph{}, err
+				}
+				if client.CodeOf(err) != client.CodeNotFound {
+					g.Failures = append(g.Failures, graphFailure{Word: word, Code: client.CodeOf(err), Error: err.Error()})
+				}
+				continue
+			}
+			for _, rel := range related[i] {
+				if !slices.Contains(types, rel.Relationshiptype) {
+					continue
+				}
+				for _, to := range rel.Words {
+					key := strings.ToLower(to)
+					if !seen[key] {
+						if len(g.Nodes) >= maxNodes {
+							g.Truncated = true
+							continue
+						}
+						seen[key] = true
+						g.Nodes = append(g.Nodes, graphNode{Word: to, Depth: level + 1})
+						next = append(next, to)
+					}
+					g.addEdge(edges, word, to, rel.Relationshiptype)
+				}
+			}
+		}
+		frontier = next
+	}
+	return g, nil
+}
+
+// addEdge adds the edge from one word to another unless it, or its reverse
+// for a symmetric relationship, is already in the graph.
+func (g *wordGraph) addEdge(seen map[[3]string]bool, from, to, typ string) {
+	from, to = g.nodeWord(from), g.nodeWord(to)
+	if from == to {
+		return
+	}
+	key := [3]string{strings.ToLower(from), strings.ToLower(to), typ}
+	reverse := [3]string{key[1], key[0], typ}
+	if seen[key] || (symmetricRelations[typ] && seen[reverse]) {
+		return
+	}
+	seen[key] = true
+	g.Edges = append(g.Edges, graphEdge{From: from, To: to, Type: typ, Directed: !symmetricRelations[typ]})
+}
+
+// nodeWord returns the spelling of word that its node has, since related
+// words may differ in case.
+func (g *wordGraph) nodeWord(word string) string {
+	for _, n := range g.Nodes {
+		if strings.EqualFold(n.Word, word) {
+			return n.Word
+		}
+	}
+	return word
+}
+
+// nodeIDs numbers the nodes n0, n1, ... for the DOT and Mermaid outputs.
+func (g wordGraph) nodeIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.Word] = "n" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+// graphDOTText renders the graph in the Graphviz DOT language. Symmetric
+// relationships are drawn without arrowheads.
+func graphDOTText(g wordGraph) string {
+	ids := g.nodeIDs()
+	var b strings.Builder
+	b.WriteString("digraph word_graph {\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for i, n := range g.Nodes {
+		style := ""
+		if i == 0 {
+			style = ", style=\"rounded,bold\""
+		}
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", ids[n.Word], strconv.Quote(n.Word), style)
+	}
+	for _, e := range g.Edges {
+		dir := ""
+		if !e.Directed {
+			dir = ", dir=none"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s%s];\n", ids[e.From], ids[e.To], strconv.Quote(e.Type), dir)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// graphMermaidText renders the graph as a Mermaid flowchart. Symmetric
+// relationships are drawn as open links.
+func graphMermaidText(g wordGraph) string {
+	ids := g.nodeIDs()
+	label := strings.NewReplacer(`"`, "#quot;")
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.Word], label.Replace(n.Word))
+	}
+	for _, e := range g.Edges {
+		link := "---"
+		if e.Directed {
+			link = "-->"
+		}
+		fmt.Fprintf(&b, "  %s -- \"%s\" %s %s\n", ids[e.From], label.Replace(e.Type), link, ids[e.To])
+	}
+	fmt.Fprintf(&b, "  classDef root stroke-width:3px\n  class %s root\n", ids[g.Root])
+	return b.String()
+}
+
+func CreateWordgraphTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("word_graph",
+		mcp.WithDescription("Explores the relationships around a word breadth first, following the chosen relationship types to a given depth, and returns the nodes and edges as JSON, Graphviz DOT or Mermaid"),
+		mcp.WithString("word", mcp.Required(), mcp.Description("Word at the center of the graph")),
+		mcp.WithArray("relationshipTypes", mcp.WithStringItems(), mcp.Description("Relationship types to follow, such as synonym, antonym or hypernym (default synonym)")),
+		mcp.WithNumber("depth", mcp.Description(fmt.Sprintf("Number of hops from the word (at most %d)", maxGraphDepth)), mcp.DefaultNumber(defaultGraphDepth)),
+		mcp.WithNumber("maxNodes", mcp.Description(fmt.Sprintf("Maximum number of words in the graph (at most %d)", maxGraphNodes)), mcp.DefaultNumber(defaultGraphNodes)),
+		mcp.WithNumber("limitPerRelationshipType", mcp.Description(fmt.Sprintf("Maximum number of related words followed per word and relationship type (at most %d)", maxGraphPerType)), mcp.DefaultNumber(defaultGraphPerType)),
+		mcp.WithString("format", mcp.Description("Text output format; the structured content is always the JSON graph"), mcp.Enum(graphJSON, graphDOT, graphMermaid)),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    WordgraphHandler(cfg),
+	}
+}
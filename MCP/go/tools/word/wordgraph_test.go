@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func newMock(t *testing.T, opts wordnikmock.Options) (*wordnikmock.Server, *config.APIConfig) {
+	t.Helper()
+	srv, err := wordnikmock.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, &config.APIConfig{BaseURL: ts.URL + "/v4", APIKey: opts.APIKey}
+}
+
+func nodeWords(g wordGraph) []string {
+	var out []string
+	for _, n := range g.Nodes {
+		out = append(out, n.Word)
+	}
+	return out
+}
+
+func edgeList(g wordGraph) []string {
+	var out []string
+	for _, e := range g.Edges {
+		link := " - "
+		if e.Directed {
+			link = " > "
+		}
+		out = append(out, e.From+link+e.To+" "+e.Type)
+	}
+	return out
+}
+
+func TestExpandGraph(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{})
+	tests := []struct {
+		desc      string
+		types     []string
+		depth     int
+		maxNodes  int
+		nodes     []string
+		edges     []string
+		truncated bool
+	}{
+		// cat and feline are synonyms of each other: one edge.
+		{"synonyms", []string{"synonym"}, 2, 50,
+			[]string{"cat", "feline", "kitty", "puss", "tabby"},
+			[]string{"cat - feline synonym", "cat - kitty synonym", "cat - puss synonym", "feline - kitty synonym", "feline - tabby synonym"}, false},
+		{"one hop", []string{"synonym"}, 1, 50,
+			[]string{"cat", "feline", "kitty", "puss"},
+			[]string{"cat - feline synonym", "cat - kitty synonym", "cat - puss synonym"}, false},
+		{"directed", []string{"synonym", "hypernym"}, 2, 50,
+			[]string{"cat", "feline", "kitty", "puss", "tabby", "mammal"},
+			[]string{"cat - feline synonym", "cat - kitty synonym", "cat - puss synonym", "feline - kitty synonym", "feline - tabby synonym", "feline > mammal hypernym"}, false},
+		// Words past maxNodes are left out with their edges.
+		{"truncated", []string{"synonym"}, 2, 3,
+			[]string{"cat", "feline", "kitty"},
+			[]string{"cat - feline synonym", "cat - kitty synonym", "feline - kitty synonym"}, true},
+		{"other types", []string{"antonym"}, 2, 50, []string{"cat"}, nil, false},
+	}
+	for _, tt := range tests {
+		g, err := expandGraph(context.Background(), cfg, "cat", tt.types, tt.depth, tt.maxNodes, url.Values{})
+		if err != nil {
+			t.Errorf("%s: %v", tt.desc, err)
+			continue
+		}
+		if got := nodeWords(g); !slices.Equal(got, tt.nodes) {
+			t.Errorf("%s: nodes %q, want %q", tt.desc, got, tt.nodes)
+		}
+		if got := edgeList(g); !slices.Equal(got, tt.edges) {
+			t.Errorf("%s: edges %q, want %q", tt.desc, got, tt.edges)
+		}
+		if g.Truncated != tt.truncated || len(g.Failures) != 0 {
+			t.Errorf("%s: truncated %v, failures %+v", tt.desc, g.Truncated, g.Failures)
+		}
+	}
+}
+
+func TestExpandGraphFailures(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{Faults: []wordnikmock.Fault{
+		{Kind: wordnikmock.Malformed, Pattern: "/word.json/feline/relatedWords"},
+	}})
+	g, err := expandGraph(context.Background(), cfg, "cat", []string{"synonym"}, 2, 50, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// feline failed and kitty and puss have no related words, which is
+	// not a failure.
+	if len(g.Failures) != 1 || g.Failures[0].Word != "feline" || g.Failures[0].Code != client.CodeDecodeError {
+		t.Errorf("failures %+v", g.Failures)
+	}
+	if got, want := nodeWords(g), []string{"cat", "feline", "kitty", "puss"}; !slices.Equal(got, want) {
+		t.Errorf("nodes %q, want %q", got, want)
+	}
+
+	// The root's failure fails the graph.
+	if _, err := expandGraph(context.Background(), cfg, "feline", []string{"synonym"}, 2, 50, url.Values{}); client.CodeOf(err) != client.CodeDecodeError {
+		t.Errorf("root failure: %v", err)
+	}
+	if _, err := expandGraph(context.Background(), cfg, "zzyzx", []string{"synonym"}, 2, 50, url.Values{}); client.CodeOf(err) != client.CodeNotFound {
+		t.Errorf("unknown root: %v", err)
+	}
+}
+
+func TestAddEdge(t *testing.T) {
+	g := wordGraph{Nodes: []graphNode{{Word: "Cat"}, {Word: "feline", Depth: 1}}}
+	seen := map[[3]string]bool{}
+	g.addEdge(seen, "cat", "FELINE", "synonym")
+	// The reverse of a symmetric edge, in any case, is the same edge.
+	g.addEdge(seen, "Feline", "CAT", "synonym")
+	g.addEdge(seen, "cat", "feline", "synonym")
+	// Directed edges are kept both ways, once each.
+	g.addEdge(seen, "feline", "cat", "hypernym")
+	g.addEdge(seen, "cat", "feline", "hypernym")
+	g.addEdge(seen, "FELINE", "cat", "hypernym")
+	// Edges to words without a node keep their spelling.
+	g.addEdge(seen, "cat", "Kitty", "synonym")
+	// No loops.
+	g.addEdge(seen, "cat", "CAT", "synonym")
+
+	want := []string{"Cat - feline synonym", "feline > Cat hypernym", "Cat > feline hypernym", "Cat - Kitty synonym"}
+	if got := edgeList(g); !slices.Equal(got, want) {
+		t.Errorf("edges %q, want %q", got, want)
+	}
+}
+
+// testGraph has an undirected and a directed edge and a word that needs
+// quoting.
+var testGraph = wordGraph{
+	Root:  "cat",
+	Nodes: []graphNode{{Word: "cat"}, {Word: `"puss"`, Depth: 1}, {Word: "mammal", Depth: 1}},
+	Edges: []graphEdge{
+		{From: "cat", To: `"puss"`, Type: "synonym"},
+		{From: "cat", To: "mammal", Type: "hypernym", Directed: true},
+	},
+}
+
+func TestGraphDOTText(t *testing.T) {
+	want := `digraph word_graph {
+  node [shape=box, style=rounded];
+  n0 [label="cat", style="rounded,bold"];
+  n1 [label="\"puss\""];
+  n2 [label="mammal"];
+  n0 -> n1 [label="synonym", dir=none];
+  n0 -> n2 [label="hypernym"];
+}
+`
+	if got := graphDOTText(testGraph); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGraphMermaidText(t *testing.T) {
+	want := `graph LR
+  n0["cat"]
+  n1["#quot;puss#quot;"]
+  n2["mammal"]
+  n0 -- "synonym" --- n1
+  n0 -- "hypernym" --> n2
+  classDef root stroke-width:3px
+  class n0 root
+`
+	if got := graphMermaidText(testGraph); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWordgraphHandler(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{})
+	handler := WordgraphHandler(cfg)
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		var req mcp.CallToolRequest
+		req.Params.Arguments = args
+		res, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	text := func(res *mcp.CallToolResult) string {
+		if len(res.Content) == 0 {
+			return ""
+		}
+		if c, ok := res.Content[0].(mcp.TextContent); ok {
+			return c.Text
+		}
+		return ""
+	}
+
+	bad := []map[string]any{
+		{},
+		{"word": "cat", "relationshipTypes": []any{"cousin"}},
+		{"word": "cat", "depth": float64(maxGraphDepth + 1)},
+		{"word": "cat", "maxNodes": float64(0)},
+		{"word": "cat", "limitPerRelationshipType": float64(maxGraphPerType + 1)},
+		{"word": "cat", "limitPerRelationshipType": float64(0)},
+		{"word": "cat", "format": "svg"},
+	}
+	for _, args := range bad {
+		if res := call(args); !res.IsError || !strings.HasPrefix(text(res), string(client.CodeBadInput)) {
+			t.Errorf("%v: %s", args, text(res))
+		}
+	}
+
+	res := call(map[string]any{"word": "cat", "limitPerRelationshipType": float64(maxGraphPerType), "format": graphDOT})
+	if res.IsError || !strings.HasPrefix(text(res), "digraph word_graph {") {
+		t.Errorf("DOT: %s", text(res))
+	}
+	if g, ok := res.StructuredContent.(wordGraph); !ok || len(g.Nodes) != 5 {
+		t.Errorf("structured content %+v", res.StructuredContent)
+	}
+}
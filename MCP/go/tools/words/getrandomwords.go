@@ -3,98 +3,60 @@ package tools
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"net/url"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
 	"github.com/wordnik/mcp-server/config"
 	"github.com/wordnik/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetrandomwordsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
 		}
-		queryParams := make([]string, 0)
+		query := url.Values{}
 		if val, ok := args["hasDictionaryDef"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("hasDictionaryDef=%v", val))
+			query.Set("hasDictionaryDef", client.QueryValue(val))
 		}
 		if val, ok := args["includePartOfSpeech"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("includePartOfSpeech=%v", val))
+			query.Set("includePartOfSpeech", client.QueryValue(val))
 		}
 		if val, ok := args["excludePartOfSpeech"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("excludePartOfSpeech=%v", val))
+			query.Set("excludePartOfSpeech", client.QueryValue(val))
 		}
 		if val, ok := args["minCorpusCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("minCorpusCount=%v", val))
+			query.Set("minCorpusCount", client.QueryValue(val))
 		}
 		if val, ok := args["maxCorpusCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("maxCorpusCount=%v", val))
+			query.Set("maxCorpusCount", client.QueryValue(val))
 		}
 		if val, ok := args["minDictionaryCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("minDictionaryCount=%v", val))
+			query.Set("minDictionaryCount", client.QueryValue(val))
 		}
 		if val, ok := args["maxDictionaryCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("maxDictionaryCount=%v", val))
+			query.Set("maxDictionaryCount", client.QueryValue(val))
 		}
 		if val, ok := args["minLength"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("minLength=%v", val))
+			query.Set("minLength", client.QueryValue(val))
 		}
 		if val, ok := args["maxLength"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("maxLength=%v", val))
+			query.Set("maxLength", client.QueryValue(val))
 		}
 		if val, ok := args["sortBy"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("sortBy=%v", val))
+			query.Set("sortBy", client.QueryValue(val))
 		}
 		if val, ok := args["sortOrder"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("sortOrder=%v", val))
+			query.Set("sortOrder", client.QueryValue(val))
 		}
 		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
-		}
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			queryParams = append(queryParams, fmt.Sprintf("api_key=%s", cfg.APIKey))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/words.json/randomWords%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			// API key already added to query string
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			query.Set("limit", client.QueryValue(val))
 		}
-		// Use properly typed response
-		var result []WordObject
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		var result []models.WordObject
+		if err := client.Get(ctx, cfg, client.Path("words.json", "randomWords"), query, &result); err != nil {
+			return client.ErrorResult(err), nil
 		}
 
 		prettyJSON, err := json.MarshalIndent(result, "", "  ")
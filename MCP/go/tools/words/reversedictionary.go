@@ -3,110 +3,72 @@ package tools
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"net/url"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
 	"github.com/wordnik/mcp-server/config"
 	"github.com/wordnik/mcp-server/models"
-	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func ReversedictionaryHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]any)
-		if !ok {
-			return mcp.NewToolResultError("Invalid arguments object"), nil
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
 		}
-		queryParams := make([]string, 0)
+		query := url.Values{}
 		if val, ok := args["query"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("query=%v", val))
+			query.Set("query", client.QueryValue(val))
 		}
 		if val, ok := args["findSenseForWord"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("findSenseForWord=%v", val))
+			query.Set("findSenseForWord", client.QueryValue(val))
 		}
 		if val, ok := args["includeSourceDictionaries"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("includeSourceDictionaries=%v", val))
+			query.Set("includeSourceDictionaries", client.QueryValue(val))
 		}
 		if val, ok := args["excludeSourceDictionaries"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("excludeSourceDictionaries=%v", val))
+			query.Set("excludeSourceDictionaries", client.QueryValue(val))
 		}
 		if val, ok := args["includePartOfSpeech"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("includePartOfSpeech=%v", val))
+			query.Set("includePartOfSpeech", client.QueryValue(val))
 		}
 		if val, ok := args["excludePartOfSpeech"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("excludePartOfSpeech=%v", val))
+			query.Set("excludePartOfSpeech", client.QueryValue(val))
 		}
 		if val, ok := args["minCorpusCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("minCorpusCount=%v", val))
+			query.Set("minCorpusCount", client.QueryValue(val))
 		}
 		if val, ok := args["maxCorpusCount"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("maxCorpusCount=%v", val))
+			query.Set("maxCorpusCount", client.QueryValue(val))
 		}
 		if val, ok := args["minLength"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("minLength=%v", val))
+			query.Set("minLength", client.QueryValue(val))
 		}
 		if val, ok := args["maxLength"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("maxLength=%v", val))
+			query.Set("maxLength", client.QueryValue(val))
 		}
 		if val, ok := args["expandTerms"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("expandTerms=%v", val))
+			query.Set("expandTerms", client.QueryValue(val))
 		}
 		if val, ok := args["includeTags"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("includeTags=%v", val))
+			query.Set("includeTags", client.QueryValue(val))
 		}
 		if val, ok := args["sortBy"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("sortBy=%v", val))
+			query.Set("sortBy", client.QueryValue(val))
 		}
 		if val, ok := args["sortOrder"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("sortOrder=%v", val))
+			query.Set("sortOrder", client.QueryValue(val))
 		}
 		if val, ok := args["skip"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("skip=%v", val))
+			query.Set("skip", client.QueryValue(val))
 		}
 		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
-		}
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			queryParams = append(queryParams, fmt.Sprintf("api_key=%s", cfg.APIKey))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/words.json/reverseDictionary%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			// API key already added to query string
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			query.Set("limit", client.QueryValue(val))
 		}
-		// Use properly typed response
 		var result models.DefinitionSearchResults
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if err := client.Get(ctx, cfg, client.Path("words.json", "reverseDictionary"), query, &result); err != nil {
+			return client.ErrorResult(err), nil
 		}
 
 		prettyJSON, err := json.MarshalIndent(result, "", "  ")
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+	"github.com/wordnik/mcp-server/wotd"
+)
+
+func WordofthedayarchiveHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := client.Arguments(request)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		startDate, _ := args["startDate"].(string)
+		endDate, _ := args["endDate"].(string)
+		if startDate == "" || endDate == "" {
+			return client.ErrorResult(client.BadInput("startDate and endDate are required")), nil
+		}
+		format := wotd.FormatJSON
+		if val, ok := args["format"].(string); ok && val != "" {
+			format = val
+		}
+		concurrency := wotd.DefaultConcurrency
+		if val, ok := args["concurrency"].(float64); ok {
+			concurrency = int(val)
+		}
+
+		dates, err := wotd.ParseRange(startDate, endDate)
+		if err != nil {
+			return client.ErrorResult(err), nil
+		}
+		archive := wotd.Fetch(ctx, cfg, dates, concurrency)
+		if len(archive.Records) == 0 && len(archive.Failures) > 0 {
+			first := archive.Failures[0]
+			return client.ErrorResult(&client.Error{
+				Code:    first.Code,
+				Message: fmt.Sprintf("all %d dates failed, first error: %s", len(archive.Failures), first.Error),
+			}), nil
+		}
+
+		var out strings.Builder
+		if err := wotd.Write(&out, format, archive.Records); err != nil {
+			return client.ErrorResult(client.BadInput("%v", err)), nil
+		}
+		result := mcp.NewToolResultText(out.String())
+		if len(archive.Failures) > 0 {
+			lines := make([]string, len(archive.Failures))
+			for i, f := range archive.Failures {
+				lines[i] = fmt.Sprintf("%s: %s", f.Date, f.Error)
+			}
+			result.Content = append(result.Content, mcp.NewTextContent(
+				fmt.Sprintf("%d of %d dates could not be fetched:\n%s", len(archive.Failures), len(dates), strings.Join(lines, "\n"))))
+		}
+		return result, nil
+	}
+}
+
+func CreateWordofthedayarchiveTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("word_of_the_day_archive",
+		mcp.WithDescription("Returns the words of the day for a date range as an archive with definitions and examples flattened"),
+		mcp.WithString("startDate", mcp.Required(), mcp.Description("First date in yyyy-MM-dd")),
+		mcp.WithString("endDate", mcp.Required(), mcp.Description(fmt.Sprintf("Last date in yyyy-MM-dd, inclusive. At most %d days after startDate", wotd.MaxDays-1))),
+		mcp.WithString("format", mcp.Description("Output format"), mcp.Enum(wotd.FormatJSON, wotd.FormatJSONL, wotd.FormatCSV)),
+		mcp.WithNumber("concurrency", mcp.Description(fmt.Sprintf("Maximum number of dates fetched in parallel (at most %d)", wotd.MaxConcurrency)), mcp.DefaultNumber(wotd.DefaultConcurrency)),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    WordofthedayarchiveHandler(cfg),
+	}
+}
@@ -0,0 +1,89 @@
+package wordnikmock
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Malformed is the Fault kind that answers 200 OK with a body that is not
+// valid JSON.
+const Malformed = "malformed"
+
+// Fault is a failure injected into matching requests.
+type Fault struct {
+	// Kind is an HTTP status code such as "429" or "500", or Malformed.
+	Kind string
+	// Pattern is a path.Match pattern for the request path without the /v4
+	// prefix, such as "/word.json/*/definitions". Empty matches every path.
+	Pattern string
+	// Probability is the share of matching requests that fail, between 0
+	// and 1. Zero means every matching request fails.
+	Probability float64
+}
+
+// ParseFault parses a fault written as "kind [probability] [pattern]", for
+// example "429", "500 0.25" or "malformed /word.json/*/definitions".
+func ParseFault(spec string) (Fault, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 3 {
+		return Fault{}, fmt.Errorf("invalid fault %q: want \"kind [probability] [pattern]\"", spec)
+	}
+	f := Fault{Kind: fields[0]}
+	if f.Kind != Malformed {
+		if status, err := strconv.Atoi(f.Kind); err != nil || status < 400 || status > 599 {
+			return Fault{}, fmt.Errorf("invalid fault kind %q: want an HTTP error status or %q", f.Kind, Malformed)
+		}
+	}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "/") {
+			if _, err := path.Match(field, ""); err != nil {
+				return Fault{}, fmt.Errorf("invalid fault pattern %q: %v", field, err)
+			}
+			f.Pattern = field
+			continue
+		}
+		p, err := strconv.ParseFloat(field, 64)
+		if err != nil || p < 0 || p > 1 {
+			return Fault{}, fmt.Errorf("invalid fault probability %q: want a number from 0 to 1", field)
+		}
+		f.Probability = p
+	}
+	return f, nil
+}
+
+func (f Fault) String() string {
+	s := f.Kind
+	if f.Probability > 0 {
+		s += " " + strconv.FormatFloat(f.Probability, 'g', -1, 64)
+	}
+	if f.Pattern != "" {
+		s += " " + f.Pattern
+	}
+	return s
+}
+
+func (f Fault) matches(p string) bool {
+	if f.Pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(f.Pattern, p)
+	return ok
+}
+
+func (f Fault) write(w http.ResponseWriter) {
+	if f.Kind == Malformed {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"word": "cat", "definitions": [`))
+		return
+	}
+	status, _ := strconv.Atoi(f.Kind)
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, status, "API rate limit exceeded")
+		return
+	}
+	writeStatus(w, status)
+}
@@ -0,0 +1,427 @@
+// Package wordnikmock is a local stand-in for the Wordnik API, so that the
+// tools and the MCP server can be tested and developed without network
+// access or an API key. It serves every path of the repository's
+// openapi.yml from fixture files, and can add latency, inject failures and
+// check the api_key parameter.
+//
+// Fixtures are JSON files named after the request path, below an optional
+// /v4 prefix: /word.json/cat/definitions is served from
+// word.json/cat/definitions.json and /words.json/randomWord from
+// words.json/randomWord.json. Words are path-escaped to name their
+// directory, so "AC/DC" is served from word.json/AC%2FDC and "summer's"
+// from word.json/summer%27s.
+// A date query parameter selects words.json/wordOfTheDay/{date}.json when
+// it exists. /words.json/search has no fixtures; it searches the words
+// that have fixtures and those listed in words.txt. A word without a
+// fixture for the requested resource gets a 404, as from the real API.
+//
+// The audio files of the audio fixtures are served too, from audio/{name},
+// at /audio/{name} without an api_key. File URLs in the fixtures that start
+// with https://audio.example/ are rewritten to point there.
+package wordnikmock
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFixtures are the fixtures compiled into the package: a handful of
+// words, such as "cat" and "serendipity", with every word resource, and the
+// words.json endpoints.
+//
+//go:embed fixtures
+var embedded embed.FS
+
+// DefaultFixtures returns the embedded fixtures.
+func DefaultFixtures() fs.FS {
+	sub, err := fs.Sub(embedded, "fixtures")
+	if err != nil {
+		panic("wordnikmock: " + err.Error())
+	}
+	return sub
+}
+
+// Options configure a Server.
+type Options struct {
+	// Fixtures holds the responses; nil means DefaultFixtures.
+	Fixtures fs.FS
+	// APIKey, if set, must be sent as the api_key query parameter; other
+	// requests get 401 Unauthorized.
+	APIKey string
+	// Latency delays every response.
+	Latency time.Duration
+	// Faults are injected into matching requests, the first match winning.
+	Faults []Fault
+	// Seed seeds the choice of which requests a partial fault hits, so
+	// that runs are repeatable.
+	Seed int64
+}
+
+// Request is a request the server received, for assertions in tests.
+type Request struct {
+	// Path is the escaped request path without the /v4 prefix, as in
+	// "/word.json/AC%2FDC/definitions".
+	Path   string
+	Query  url.Values
+	Status int
+}
+
+// Server is an http.Handler serving the fixtures. It is safe for
+// concurrent use.
+type Server struct {
+	fixtures fs.FS
+	apiKey   string
+	words    []string
+
+	mu       sync.Mutex
+	latency  time.Duration
+	faults   []Fault
+	rand     *rand.Rand
+	requests []Request
+}
+
+// New returns a server for opts. It fails if the fixtures cannot be read.
+func New(opts Options) (*Server, error) {
+	fixtures := opts.Fixtures
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	words, err := knownWords(fixtures)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		fixtures: fixtures,
+		apiKey:   opts.APIKey,
+		words:    words,
+		latency:  opts.Latency,
+		faults:   append([]Fault(nil), opts.Faults...),
+		rand:     rand.New(rand.NewSource(opts.Seed)),
+	}, nil
+}
+
+// knownWords lists the words with fixtures and those in words.txt, sorted.
+func knownWords(fixtures fs.FS) ([]string, error) {
+	seen := map[string]bool{}
+	entries, err := fs.ReadDir(fixtures, "word.json")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if word, err := url.PathUnescape(e.Name()); err == nil {
+				seen[word] = true
+			}
+		}
+	}
+	data, err := fs.ReadFile(fixtures, "words.txt")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			seen[line] = true
+		}
+	}
+	words := make([]string, 0, len(seen))
+	for w := range seen {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	return words, nil
+}
+
+// SetLatency changes the delay added to every response.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetFaults replaces the injected faults.
+func (s *Server) SetFaults(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append([]Fault(nil), faults...)
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets the requests received so far.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// Words returns the words /words.json/search knows, sorted.
+func (s *Server) Words() []string {
+	return append([]string(nil), s.words...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.EscapedPath(), "/v4")
+	query := r.URL.Query()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Path: p, Query: query, Status: rec.status})
+		s.mu.Unlock()
+	}()
+
+	s.mu.Lock()
+	latency := s.latency
+	fault, faulted := s.pickFault(p)
+	s.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			rec.status = 499 // client closed the request
+			return
+		}
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeStatus(rec, http.StatusMethodNotAllowed)
+		return
+	}
+	if s.apiKey != "" && query.Get("api_key") != s.apiKey && !strings.HasPrefix(p, "/audio/") {
+		writeStatus(rec, http.StatusUnauthorized)
+		return
+	}
+	if faulted {
+		fault.write(rec)
+		return
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	s.serve(rec, p, query, scheme+"://"+r.Host)
+}
+
+// pickFault returns the first fault matching p, if it hits this request.
+// s.mu must be held.
+func (s *Server) pickFault(p string) (Fault, bool) {
+	for _, f := range s.faults {
+		if !f.matches(p) {
+			continue
+		}
+		if f.Probability > 0 && f.Probability < 1 && s.rand.Float64() >= f.Probability {
+			return Fault{}, false
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// audioURLPrefix starts the file URLs of the audio fixtures.
+const audioURLPrefix = "https://audio.example/"
+
+// serve serves the request for p. base is the URL of the server, which
+// audio file URLs are rewritten to.
+func (s *Server) serve(w http.ResponseWriter, p string, query url.Values, base string) {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i, seg := range segments {
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid path")
+			return
+		}
+		segments[i] = unescaped
+	}
+	switch {
+	case len(segments) == 3 && segments[0] == "word.json":
+		s.serveWord(w, segments[1], segments[2], query, base)
+	case len(segments) == 2 && segments[0] == "audio":
+		s.serveAudio(w, segments[1])
+	case len(segments) == 3 && segments[0] == "words.json" && segments[1] == "search":
+		s.serveSearch(w, segments[2], query)
+	case len(segments) == 2 && segments[0] == "words.json":
+		name := "words.json/" + segments[1]
+		if date := query.Get("date"); date != "" && segments[1] == "wordOfTheDay" {
+			if data, err := fs.ReadFile(s.fixtures, name+"/"+date+".json"); err == nil {
+				writeJSON(w, data, query)
+				return
+			}
+		}
+		s.serveFile(w, name+".json", query)
+	default:
+		writeStatus(w, http.StatusNotFound)
+	}
+}
+
+// serveWord serves a word resource. Words are looked up as given and then
+// in lower case, and with useCanonical=true also without a plural "s".
+func (s *Server) serveWord(w http.ResponseWriter, word, resource string, query url.Values, base string) {
+	candidates := []string{word, strings.ToLower(word)}
+	if query.Get("useCanonical") == "true" {
+		lower := strings.ToLower(word)
+		candidates = append(candidates, strings.TrimSuffix(lower, "s"), strings.TrimSuffix(lower, "es"))
+	}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if data, err := fs.ReadFile(s.fixtures, path.Join("word.json", fixtureName(c), resource+".json")); err == nil {
+			if resource == "audio" {
+				data = bytes.ReplaceAll(data, []byte(audioURLPrefix), []byte(base+"/audio/"))
+			}
+			writeJSON(w, data, query)
+			return
+		}
+	}
+	writeStatus(w, http.StatusNotFound)
+}
+
+// serveAudio serves an audio file of the audio fixtures.
+func (s *Server) serveAudio(w http.ResponseWriter, name string) {
+	data, err := fs.ReadFile(s.fixtures, path.Join("audio", fixtureName(name)))
+	if err != nil {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "audio/mpeg")
+	w.Write(data)
+}
+
+// fixtureName is the name of the fixture directory for word: its
+// path-escaped form, since go:embed skips names with characters such as
+// "'" and no directory can be named "AC/DC". Escaping "." and ".." keeps
+// every word inside word.json.
+func fixtureName(word string) string {
+	switch word {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	return url.PathEscape(word)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, name string, query url.Values) {
+	data, err := fs.ReadFile(s.fixtures, name)
+	if err != nil {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, data, query)
+}
+
+type searchResult struct {
+	Word       string  `json:"word"`
+	Lexicality float64 `json:"lexicality"`
+	Count      int64   `json:"count"`
+}
+
+// serveSearch searches the known words for q, as a regular expression
+// when allowRegex=true and as a substring otherwise, honoring
+// caseSensitive, minLength, maxLength, skip and limit.
+func (s *Server) serveSearch(w http.ResponseWriter, q string, query url.Values) {
+	caseSensitive := query.Get("caseSensitive") != "false"
+	var match func(string) bool
+	if query.Get("allowRegex") == "true" {
+		expr := q
+		if !caseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid regular expression")
+			return
+		}
+		match = re.MatchString
+	} else if caseSensitive {
+		match = func(word string) bool { return strings.Contains(word, q) }
+	} else {
+		lower := strings.ToLower(q)
+		match = func(word string) bool { return strings.Contains(strings.ToLower(word), lower) }
+	}
+	minLength := intQuery(query, "minLength", 1)
+	maxLength := intQuery(query, "maxLength", -1)
+	results := []searchResult{}
+	for _, word := range s.words {
+		n := len([]rune(word))
+		if n < minLength || (maxLength >= 0 && n > maxLength) || !match(word) {
+			continue
+		}
+		results = append(results, searchResult{Word: word, Lexicality: 1, Count: 1})
+	}
+	total := len(results)
+	skip := min(max(intQuery(query, "skip", 0), 0), len(results))
+	results = results[skip:]
+	if limit := intQuery(query, "limit", 10); limit >= 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	data, _ := json.Marshal(struct {
+		SearchResults []searchResult `json:"searchResults"`
+		TotalResults  int            `json:"totalResults"`
+	}{results, total})
+	writeJSON(w, data, nil)
+}
+
+func intQuery(query url.Values, key string, fallback int) int {
+	n, err := strconv.Atoi(query.Get(key))
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// writeJSON writes a fixture. A top-level array is cut to the limit query
+// parameter, as the API does.
+func writeJSON(w http.ResponseWriter, data []byte, query url.Values) {
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 0 {
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) == nil && limit < len(items) {
+			data, _ = json.Marshal(items[:limit])
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
+
+// writeStatus writes an error body shaped like the API's.
+func writeStatus(w http.ResponseWriter, status int) {
+	writeError(w, status, http.StatusText(status))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	data, _ := json.Marshal(map[string]any{
+		"statusCode": status,
+		"error":      http.StatusText(status),
+		"message":    message,
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
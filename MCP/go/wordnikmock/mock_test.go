@@ -0,0 +1,306 @@
+package wordnikmock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	t.Helper()
+	srv, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, ts
+}
+
+func get(t *testing.T, ts *httptest.Server, target string) (int, http.Header, []byte) {
+	t.Helper()
+	resp, err := http.Get(ts.URL + target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compact JSON so that tests need not follow the fixtures' layout.
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return resp.StatusCode, resp.Header, body
+}
+
+func TestServeFixtures(t *testing.T) {
+	_, ts := newServer(t, Options{})
+	tests := []struct {
+		target string
+		status int
+		want   string
+	}{
+		{"/v4/word.json/cat/definitions", 200, `"text":"A small carnivorous mammal`},
+		{"/word.json/cat/scrabbleScore", 200, `5`},
+		{"/v4/word.json/CAT/definitions", 200, `"word":"cat"`},
+		{"/v4/word.json/cats/definitions", 404, `"statusCode":404`},
+		{"/v4/word.json/cats/definitions?useCanonical=true", 200, `"word":"cat"`},
+		{"/v4/word.json/qwxz/definitions", 404, `"Not Found"`},
+		{"/v4/word.json/cat/nonsense", 404, ``},
+		{"/v4/word.json/AC%2FDC/definitions", 200, `"word":"AC/DC"`},
+		{"/v4/word.json/AC/DC/definitions", 404, ``},
+		{"/v4/word.json/summer%27s/hyphenation", 200, `"text":"sum"`},
+		{"/v4/word.json/../fixtures/definitions", 404, ``},
+		{"/v4/words.json/randomWord", 200, `"word"`},
+		{"/v4/words.json/wordOfTheDay", 200, `"word":"serendipity"`},
+		{"/v4/words.json/wordOfTheDay?date=2024-01-01", 200, `"word":"lexicon"`},
+		{"/v4/words.json/wordOfTheDay?date=1999-01-01", 200, `"word":"serendipity"`},
+		{"/v4/account.json/apiTokenStatus", 404, ``},
+	}
+	for _, tt := range tests {
+		status, header, body := get(t, ts, tt.target)
+		if status != tt.status || !strings.Contains(string(body), tt.want) {
+			t.Errorf("GET %s = %d %s, want %d containing %q", tt.target, status, body, tt.status, tt.want)
+		}
+		if ct := header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("GET %s: Content-Type %q", tt.target, ct)
+		}
+	}
+}
+
+func TestEveryWordResourceHasFixture(t *testing.T) {
+	_, ts := newServer(t, Options{})
+	resources := []string{"audio", "definitions", "etymologies", "examples", "frequency", "hyphenation",
+		"phrases", "pronunciations", "relatedWords", "scrabbleScore", "topExample"}
+	for _, word := range []string{"cat", "serendipity"} {
+		for _, r := range resources {
+			target := "/v4/word.json/" + word + "/" + r
+			status, _, body := get(t, ts, target)
+			if status != http.StatusOK || !json.Valid(body) {
+				t.Errorf("GET %s = %d %s", target, status, body)
+			}
+		}
+	}
+}
+
+func TestAudioFiles(t *testing.T) {
+	_, ts := newServer(t, Options{APIKey: "secret"})
+	_, _, body := get(t, ts, "/v4/word.json/cat/audio?api_key=secret")
+	var files []struct {
+		FileURL string `json:"fileUrl"`
+	}
+	if err := json.Unmarshal(body, &files); err != nil || len(files) != 1 || files[0].FileURL != ts.URL+"/audio/cat.mp3" {
+		t.Fatalf("audio = %s, want a file URL under %s", body, ts.URL)
+	}
+	status, header, data := get(t, ts, "/audio/cat.mp3")
+	if status != http.StatusOK || header.Get("Content-Type") != "audio/mpeg" || !bytes.HasPrefix(data, []byte("ID3")) {
+		t.Errorf("GET audio file = %d %q %q", status, header.Get("Content-Type"), data)
+	}
+	if status, _, _ := get(t, ts, "/audio/dog.mp3"); status != http.StatusNotFound {
+		t.Errorf("missing audio file: status %d, want 404", status)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	_, ts := newServer(t, Options{})
+	_, _, all := get(t, ts, "/v4/word.json/cat/examples")
+	_, _, one := get(t, ts, "/v4/word.json/cat/definitions?limit=1")
+	var items []json.RawMessage
+	if err := json.Unmarshal(one, &items); err != nil || len(items) != 1 {
+		t.Errorf("limit=1 returned %s", one)
+	}
+	// Objects are never cut.
+	if !strings.HasPrefix(strings.TrimSpace(string(all)), "{") {
+		t.Errorf("examples fixture is not an object: %s", all)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	_, ts := newServer(t, Options{})
+	var got struct {
+		SearchResults []struct{ Word string } `json:"searchResults"`
+		TotalResults  int                     `json:"totalResults"`
+	}
+	words := func() []string {
+		var w []string
+		for _, r := range got.SearchResults {
+			w = append(w, r.Word)
+		}
+		return w
+	}
+
+	_, _, body := get(t, ts, "/v4/words.json/search/%5Eca.*$?allowRegex=true&limit=2")
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.SearchResults) != 2 || got.TotalResults <= 2 || !strings.HasPrefix(words()[0], "ca") {
+		t.Errorf("regex search = %s", body)
+	}
+
+	_, _, body = get(t, ts, "/v4/words.json/search/DIP?caseSensitive=false")
+	json.Unmarshal(body, &got)
+	if w := words(); len(w) == 0 || w[0] != "serendipity" {
+		t.Errorf("substring search = %s", body)
+	}
+
+	if status, _, _ := get(t, ts, "/v4/words.json/search/%5B?allowRegex=true"); status != http.StatusBadRequest {
+		t.Errorf("bad regex: status %d, want 400", status)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	srv, ts := newServer(t, Options{APIKey: "secret"})
+	if status, _, _ := get(t, ts, "/v4/word.json/cat/definitions"); status != http.StatusUnauthorized {
+		t.Errorf("without key: status %d, want 401", status)
+	}
+	if status, _, _ := get(t, ts, "/v4/word.json/cat/definitions?api_key=wrong"); status != http.StatusUnauthorized {
+		t.Errorf("wrong key: status %d, want 401", status)
+	}
+	if status, _, _ := get(t, ts, "/v4/word.json/cat/definitions?api_key=secret"); status != http.StatusOK {
+		t.Errorf("right key: status %d, want 200", status)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 3 || reqs[0].Status != 401 || reqs[2].Status != 200 || reqs[2].Path != "/word.json/cat/definitions" {
+		t.Errorf("Requests() = %+v", reqs)
+	}
+	srv.Reset()
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("after Reset, %d requests", n)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv, ts := newServer(t, Options{Faults: []Fault{
+		{Kind: "429", Pattern: "/word.json/*/definitions"},
+		{Kind: Malformed, Pattern: "/word.json/*/examples"},
+		{Kind: "500"},
+	}})
+
+	status, header, _ := get(t, ts, "/v4/word.json/cat/definitions")
+	if status != http.StatusTooManyRequests || header.Get("Retry-After") == "" {
+		t.Errorf("429 fault: status %d, Retry-After %q", status, header.Get("Retry-After"))
+	}
+	status, _, body := get(t, ts, "/v4/word.json/cat/examples")
+	if status != http.StatusOK || json.Valid(body) {
+		t.Errorf("malformed fault: status %d, body %s", status, body)
+	}
+	if status, _, _ := get(t, ts, "/v4/words.json/randomWord"); status != http.StatusInternalServerError {
+		t.Errorf("500 fault: status %d", status)
+	}
+
+	srv.SetFaults()
+	if status, _, _ := get(t, ts, "/v4/word.json/cat/definitions"); status != http.StatusOK {
+		t.Errorf("after SetFaults(): status %d", status)
+	}
+}
+
+func TestPartialFaultIsRepeatable(t *testing.T) {
+	run := func() []int {
+		_, ts := newServer(t, Options{Seed: 42, Faults: []Fault{{Kind: "503", Probability: 0.5}}})
+		var statuses []int
+		for range 40 {
+			status, _, _ := get(t, ts, "/v4/word.json/cat/frequency")
+			statuses = append(statuses, status)
+		}
+		return statuses
+	}
+	first, second := run(), run()
+	failed := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("runs with the same seed differ at request %d: %v vs %v", i, first, second)
+		}
+		if first[i] == http.StatusServiceUnavailable {
+			failed++
+		}
+	}
+	if failed == 0 || failed == len(first) {
+		t.Errorf("%d of %d requests failed with probability 0.5", failed, len(first))
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv, ts := newServer(t, Options{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	get(t, ts, "/v4/word.json/cat/frequency")
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("response after %v, want at least 50ms", d)
+	}
+
+	srv.SetLatency(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v4/word.json/cat/frequency", nil)
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Fatal("request succeeded despite the client timeout")
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	_, ts := newServer(t, Options{})
+	resp, err := http.Post(ts.URL+"/v4/word.json/cat/definitions", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestCustomFixtures(t *testing.T) {
+	fsys := fstest.MapFS{
+		"word.json/zebra/definitions.json": {Data: []byte(`[{"word":"zebra","text":"A striped horse."}]`)},
+		"words.txt":                        {Data: []byte("# extra words\nzeal\n\nzest\n")},
+	}
+	srv, ts := newServer(t, Options{Fixtures: fsys})
+	if got := strings.Join(srv.Words(), ","); got != "zeal,zebra,zest" {
+		t.Errorf("Words() = %s", got)
+	}
+	if status, _, body := get(t, ts, "/v4/word.json/zebra/definitions"); status != 200 || !strings.Contains(string(body), "striped") {
+		t.Errorf("custom fixture: %d %s", status, body)
+	}
+	if status, _, _ := get(t, ts, "/v4/word.json/cat/definitions"); status != http.StatusNotFound {
+		t.Errorf("word outside the custom fixtures: status %d, want 404", status)
+	}
+}
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Fault
+		ok   bool
+	}{
+		{"429", Fault{Kind: "429"}, true},
+		{"500 0.25", Fault{Kind: "500", Probability: 0.25}, true},
+		{"malformed /word.json/*/definitions", Fault{Kind: Malformed, Pattern: "/word.json/*/definitions"}, true},
+		{"503 /words.json/* 0.5", Fault{Kind: "503", Pattern: "/words.json/*", Probability: 0.5}, true},
+		{"", Fault{}, false},
+		{"200", Fault{}, false},
+		{"teapot", Fault{}, false},
+		{"500 1.5", Fault{}, false},
+		{"500 /[", Fault{}, false},
+		{"500 0.1 /a extra", Fault{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseFault(tt.spec)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseFault(%q) = %+v, %v; want %+v, ok=%v", tt.spec, got, err, tt.want, tt.ok)
+		}
+		if tt.ok {
+			if again, err := ParseFault(got.String()); err != nil || again != got {
+				t.Errorf("ParseFault(%q.String()) = %+v, %v", tt.spec, again, err)
+			}
+		}
+	}
+}
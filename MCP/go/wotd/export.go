@@ -0,0 +1,58 @@
+package wotd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats accepted by Write.
+const (
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// multiValueSeparator joins definitions and examples inside one CSV cell.
+const multiValueSeparator = " | "
+
+// Write exports records in the given format.
+func Write(w io.Writer, format string, records []Record) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"date", "word", "note", "definitions", "examples"}); err != nil {
+			return err
+		}
+		for _, r := range records {
+			row := []string{
+				r.Date,
+				r.Word,
+				r.Note,
+				strings.Join(r.Definitions, multiValueSeparator),
+				strings.Join(r.Examples, multiValueSeparator),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q (want %s, %s or %s)", format, FormatJSON, FormatJSONL, FormatCSV)
+	}
+}
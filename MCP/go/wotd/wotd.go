@@ -0,0 +1,175 @@
+// Package wotd fetches ranges of Wordnik words of the day and exports them as
+// flat archive records.
+package wotd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/models"
+)
+
+// DateLayout is the yyyy-MM-dd format used by the wordOfTheDay endpoint.
+const DateLayout = "2006-01-02"
+
+// MaxDays bounds the size of a single range request.
+const MaxDays = 366
+
+// DefaultConcurrency is the number of dates fetched in parallel. The shared
+// rate limiter still applies, so this only bounds in-flight requests.
+const DefaultConcurrency = 4
+
+// MaxConcurrency bounds the concurrency callers may ask for.
+const MaxConcurrency = 8
+
+// todayTTL is how long the current day's entry is cached; it may still be
+// edited on the day it is published.
+const todayTTL = time.Hour
+
+// Record is a word of the day flattened for export.
+type Record struct {
+	Date        string   `json:"date"`
+	Word        string   `json:"word"`
+	Note        string   `json:"note,omitempty"`
+	Definitions []string `json:"definitions"`
+	Examples    []string `json:"examples"`
+}
+
+// Failure records a date that could not be fetched.
+type Failure struct {
+	Date  string           `json:"date"`
+	Code  client.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+// Archive is the result of fetching a date range.
+type Archive struct {
+	Records  []Record  `json:"records"`
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// wordOfTheDay mirrors models.WordOfTheDay with typed definitions and
+// examples.
+type wordOfTheDay struct {
+	Word        string                    `json:"word"`
+	Note        string                    `json:"note"`
+	PublishDate string                    `json:"publishDate"`
+	Definitions []models.SimpleDefinition `json:"definitions"`
+	Examples    []models.SimpleExample    `json:"examples"`
+}
+
+// ParseRange validates a yyyy-MM-dd date range and returns its dates in order.
+func ParseRange(start, end string) ([]time.Time, error) {
+	from, err := time.Parse(DateLayout, start)
+	if err != nil {
+		return nil, client.BadInput("invalid start date %q, expected yyyy-MM-dd", start)
+	}
+	to, err := time.Parse(DateLayout, end)
+	if err != nil {
+		return nil, client.BadInput("invalid end date %q, expected yyyy-MM-dd", end)
+	}
+	if to.Before(from) {
+		return nil, client.BadInput("end date %s is before start date %s", end, start)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > MaxDays {
+		return nil, client.BadInput("range of %d days exceeds the maximum of %d", days, MaxDays)
+	}
+	dates := make([]time.Time, days)
+	for i := range dates {
+		dates[i] = from.AddDate(0, 0, i)
+	}
+	return dates, nil
+}
+
+// Fetch retrieves the word of the day for every date, running up to
+// concurrency requests at once, at most MaxConcurrency and one per date.
+// Past dates are served from the response cache
+// when available. Dates that fail are reported in Archive.Failures rather
+// than aborting the whole range.
+func Fetch(ctx context.Context, cfg *config.APIConfig, dates []time.Time, concurrency int) Archive {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	concurrency = min(concurrency, MaxConcurrency, len(dates))
+	today := time.Now().UTC().Format(DateLayout)
+
+	var (
+		mu      sync.Mutex
+		archive Archive
+		wg      sync.WaitGroup
+	)
+	jobs := make(chan time.Time)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				date := d.Format(DateLayout)
+				rec, err := fetchDay(ctx, cfg, date, today)
+				mu.Lock()
+				if err != nil {
+					archive.Failures = append(archive.Failures, Failure{Date: date, Code: client.CodeOf(err), Error: err.Error()})
+				} else {
+					archive.Records = append(archive.Records, rec)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, d := range dates {
+		jobs <- d
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(archive.Records, func(i, j int) bool { return archive.Records[i].Date < archive.Records[j].Date })
+	sort.Slice(archive.Failures, func(i, j int) bool { return archive.Failures[i].Date < archive.Failures[j].Date })
+	return archive
+}
+
+func fetchDay(ctx context.Context, cfg *config.APIConfig, date, today string) (Record, error) {
+	query := url.Values{}
+	query.Set("date", date)
+	var day wordOfTheDay
+	var err error
+	switch {
+	case date < today:
+		err = client.GetCached(ctx, cfg, client.Path("words.json", "wordOfTheDay"), query, client.Forever, &day)
+	case date == today:
+		err = client.GetCached(ctx, cfg, client.Path("words.json", "wordOfTheDay"), query, todayTTL, &day)
+	default:
+		err = client.Get(ctx, cfg, client.Path("words.json", "wordOfTheDay"), query, &day)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return flatten(date, day), nil
+}
+
+func flatten(date string, day wordOfTheDay) Record {
+	rec := Record{
+		Date:        date,
+		Word:        day.Word,
+		Note:        day.Note,
+		Definitions: make([]string, 0, len(day.Definitions)),
+		Examples:    make([]string, 0, len(day.Examples)),
+	}
+	for _, d := range day.Definitions {
+		if d.Partofspeech != "" {
+			rec.Definitions = append(rec.Definitions, fmt.Sprintf("%s: %s", d.Partofspeech, d.Text))
+		} else {
+			rec.Definitions = append(rec.Definitions, d.Text)
+		}
+	}
+	for _, e := range day.Examples {
+		rec.Examples = append(rec.Examples, e.Text)
+	}
+	return rec
+}
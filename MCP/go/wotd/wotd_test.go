@@ -0,0 +1,211 @@
+package wotd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wordnik/mcp-server/client"
+	"github.com/wordnik/mcp-server/config"
+	"github.com/wordnik/mcp-server/wordnikmock"
+)
+
+func TestParseRange(t *testing.T) {
+	dates, err := ParseRange("2024-02-27", "2024-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range dates {
+		got = append(got, d.Format(DateLayout))
+	}
+	if want := "2024-02-27 2024-02-28 2024-02-29 2024-03-01"; strings.Join(got, " ") != want {
+		t.Errorf("dates %q, want %s", got, want)
+	}
+	if dates, err := ParseRange("2024-01-01", "2024-01-01"); err != nil || len(dates) != 1 {
+		t.Errorf("single day: %v, %v", dates, err)
+	}
+	// 2024 is a leap year, so the whole year is the longest range.
+	if dates, err := ParseRange("2024-01-01", "2024-12-31"); err != nil || len(dates) != MaxDays {
+		t.Errorf("whole year: %d dates, %v", len(dates), err)
+	}
+
+	for _, r := range [][2]string{
+		{"2024-01-02", "2024-01-01"},
+		{"2024-01-01", "2025-01-01"},
+		{"2024-1-1", "2024-01-02"},
+		{"2024-01-01", "2024-02-30"},
+		{"", "2024-01-01"},
+		{"2024-01-01", "tomorrow"},
+	} {
+		if _, err := ParseRange(r[0], r[1]); client.CodeOf(err) != client.CodeBadInput {
+			t.Errorf("ParseRange(%q, %q) = %v, want bad_input", r[0], r[1], err)
+		}
+	}
+}
+
+// peakServer serves the mock and records the most requests it had in
+// flight at once.
+type peakServer struct {
+	http.Handler
+	mu             sync.Mutex
+	inFlight, peak int
+}
+
+func (s *peakServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+	s.Handler.ServeHTTP(w, r)
+}
+
+func newMock(t *testing.T, opts wordnikmock.Options) (*peakServer, *config.APIConfig) {
+	t.Helper()
+	srv, err := wordnikmock.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := &peakServer{Handler: srv}
+	ts := httptest.NewServer(ps)
+	t.Cleanup(ts.Close)
+	return ps, &config.APIConfig{BaseURL: ts.URL + "/v4"}
+}
+
+func TestFetchConcurrency(t *testing.T) {
+	dates, err := ParseRange("2023-01-01", "2023-01-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		concurrency int
+		dates       int
+		want        int
+	}{
+		{0, 20, DefaultConcurrency},
+		{2, 20, 2},
+		{MaxConcurrency * 4, 20, MaxConcurrency},
+		{MaxConcurrency, 3, 3},
+	}
+	for _, tt := range tests {
+		ps, cfg := newMock(t, wordnikmock.Options{Latency: 50 * time.Millisecond})
+		archive := Fetch(context.Background(), cfg, dates[:tt.dates], tt.concurrency)
+		if len(archive.Records) != tt.dates || len(archive.Failures) != 0 {
+			t.Errorf("concurrency %d: %d records, failures %+v", tt.concurrency, len(archive.Records), archive.Failures)
+		}
+		if ps.peak != tt.want {
+			t.Errorf("concurrency %d over %d dates: %d requests at once, want %d", tt.concurrency, tt.dates, ps.peak, tt.want)
+		}
+	}
+	// An empty range sends nothing.
+	if archive := Fetch(context.Background(), &config.APIConfig{}, nil, 4); len(archive.Records)+len(archive.Failures) != 0 {
+		t.Errorf("empty range: %+v", archive)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	_, cfg := newMock(t, wordnikmock.Options{})
+	dates, err := ParseRange("2023-12-31", "2024-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := Fetch(context.Background(), cfg, dates, 3)
+	if len(archive.Records) != 3 || len(archive.Failures) != 0 {
+		t.Fatalf("archive %+v", archive)
+	}
+	for i, want := range []string{"2023-12-31", "2024-01-01", "2024-01-02"} {
+		if archive.Records[i].Date != want {
+			t.Errorf("record %d dated %s, want %s", i, archive.Records[i].Date, want)
+		}
+	}
+	got := archive.Records[1]
+	if got.Word != "lexicon" || got.Note != "The mock word of the day." ||
+		strings.Join(got.Definitions, "|") != "noun: The vocabulary of a particular language." ||
+		strings.Join(got.Examples, "|") != "The museum traces the lexicon of Basque." {
+		t.Errorf("record %+v", got)
+	}
+
+	_, cfg = newMock(t, wordnikmock.Options{Faults: []wordnikmock.Fault{{Kind: wordnikmock.Malformed}}})
+	archive = Fetch(context.Background(), cfg, dates, 3)
+	if len(archive.Records) != 0 || len(archive.Failures) != 3 {
+		t.Fatalf("archive %+v", archive)
+	}
+	for i, f := range archive.Failures {
+		if f.Date != dates[i].Format(DateLayout) || f.Code != client.CodeDecodeError {
+			t.Errorf("failure %d: %+v", i, f)
+		}
+	}
+}
+
+var testRecords = []Record{
+	{Date: "2024-01-01", Word: "lexicon", Note: "A note, with a comma.", Definitions: []string{"noun: one", "verb: two"}, Examples: []string{`He said "lexicon".`}},
+	{Date: "2024-01-02", Word: "serendipity", Definitions: []string{}, Examples: []string{}},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, FormatCSV, testRecords); err != nil {
+		t.Fatal(err)
+	}
+	want := `date,word,note,definitions,examples
+2024-01-01,lexicon,"A note, with a comma.",noun: one | verb: two,"He said ""lexicon""."
+2024-01-02,serendipity,,,
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Write(&b, FormatCSV, nil); err != nil || b.String() != "date,word,note,definitions,examples\n" {
+		t.Errorf("no records: %q, %v", b.String(), err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, FormatJSONL, testRecords); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"date":"2024-01-01","word":"lexicon","note":"A note, with a comma.","definitions":["noun: one","verb: two"],"examples":["He said \"lexicon\"."]}
+{"date":"2024-01-02","word":"serendipity","definitions":[],"examples":[]}
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Write(&b, FormatJSONL, nil); err != nil || b.Len() != 0 {
+		t.Errorf("no records: %q, %v", b.String(), err)
+	}
+
+	b.Reset()
+	if err := Write(&b, FormatJSON, testRecords[1:]); err != nil {
+		t.Fatal(err)
+	}
+	want = `[
+  {
+    "date": "2024-01-02",
+    "word": "serendipity",
+    "definitions": [],
+    "examples": []
+  }
+]
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if err := Write(&b, "xml", testRecords); err == nil {
+		t.Error("unknown format written")
+	}
+}